@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Compare recorded and calculated costs",
+	Long: `Compare the costUSD recorded in the transcripts with the cost calculated from
+token usage and pricing, per model. Only messages that carry a recorded cost
+are included.`,
+	RunE: runAudit,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	opts, err := parseOptions()
+	if err != nil {
+		return err
+	}
+
+	messages, err := loadMessages(opts)
+	if err != nil {
+		return err
+	}
+
+	audits := newCalculator(opts).AuditCosts(messages)
+
+	return render(opts, display.CostAuditReport(audits))
+}
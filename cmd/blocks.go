@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+)
+
+var blocksCmd = &cobra.Command{
+	Use:   "blocks",
+	Short: "Show 5-hour billing block report",
+	Long: `Display token usage and costs grouped into the 5-hour billing windows used by
+Claude subscription limits. The active block shows elapsed and remaining time
+and a projection at the current burn rate.`,
+	RunE: runBlocks,
+}
+
+func init() {
+	rootCmd.AddCommand(blocksCmd)
+}
+
+func runBlocks(cmd *cobra.Command, args []string) error {
+	opts, err := parseOptions()
+	if err != nil {
+		return err
+	}
+
+	messages, err := loadMessages(opts)
+	if err != nil {
+		return err
+	}
+	calc := newCalculator(opts)
+	defer warnUnpriced(calc, messages)
+
+	blockUsage := calc.AggregateBlocks(messages, time.Now().In(opts.Location))
+
+	return render(opts, display.BlocksReport(blockUsage, messages, calc, opts.Ascending, opts.Breakdown))
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/parser"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the parse cache",
+	Long:  `Manage the on-disk cache of messages extracted from JSONL files.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the parse cache",
+	Long:  `Remove the parse cache so that the next report parses every file from scratch.`,
+	RunE:  runCacheClear,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	cacheDir := parser.GetCacheDir()
+	if cacheDir == "" {
+		return fmt.Errorf("could not determine the user cache directory")
+	}
+
+	if err := parser.ClearCache(cacheDir); err != nil {
+		return fmt.Errorf("error clearing cache: %w", err)
+	}
+
+	fmt.Printf("Cleared parse cache in %s\n", cacheDir)
+	return nil
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// loadCurrency finds the exchange rate that converts all costs to --currency,
+// using the rates file given with --rates-file or found in the config dir. It
+// returns the currency, its rate, which is nil for US dollars, and the rates
+// file used, if any.
+func loadCurrency() (string, *models.ExchangeRate, string, error) {
+	code := strings.ToUpper(currencyCode)
+	if code == "" || code == models.BaseCurrency {
+		return models.BaseCurrency, nil, "", nil
+	}
+
+	path := ratesFile
+	if path == "" {
+		path = models.FindRatesFile(models.GetPricingConfigDir())
+		if path == "" {
+			return "", nil, "", fmt.Errorf("no exchange rates for %s: pass --rates-file or create rates.json or rates.yaml in %s", code, models.GetPricingConfigDir())
+		}
+	}
+
+	file, err := models.LoadRatesFile(path)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("error loading rates file: %w", err)
+	}
+
+	rate, ok := file.Currencies[code]
+	if !ok {
+		return "", nil, "", fmt.Errorf("no exchange rate for %s in %s", code, path)
+	}
+
+	return code, &rate, path, nil
+}
@@ -1,9 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -30,38 +30,78 @@ func runDaily(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	projectsDir := parser.GetClaudeProjectsDir()
-	messages, err := parser.ParseJSONLFiles(projectsDir)
+	messages, err := loadMessages(opts)
 	if err != nil {
-		return fmt.Errorf("error parsing JSONL files: %w", err)
+		return err
 	}
+	calc := newCalculator(opts)
+	defer warnUnpriced(calc, messages)
 
-	messages = parser.FilterByDateRange(messages, opts.Since, opts.Until)
-	messages = parser.FilterByModels(messages, opts.Models)
+	dailyUsage := calc.AggregateDaily(messages)
 
-	dailyUsage := calculator.AggregateDaily(messages)
+	return render(opts, display.DailyReport(dailyUsage, messages, calc, opts.Ascending, opts.Breakdown))
+}
 
-	if opts.JSONOutput {
-		return outputJSON(dailyUsage)
+func parseOptions() (*models.ReportOptions, error) {
+	pricingPath, err := loadPricing()
+	if err != nil {
+		return nil, err
 	}
 
-	if opts.Breakdown {
-		return display.ShowDailyWithBreakdown(dailyUsage, messages, opts.Ascending)
+	format, err := parseFormat()
+	if err != nil {
+		return nil, err
 	}
 
-	return display.ShowDaily(dailyUsage, opts.Ascending)
-}
+	tmpl, err := loadTemplate(format)
+	if err != nil {
+		return nil, err
+	}
 
-func parseOptions() (*models.ReportOptions, error) {
 	opts := &models.ReportOptions{
-		Breakdown:  breakdown,
-		JSONOutput: jsonOutput,
-		Ascending:  ascending,
-		Models:     modelFilter,
+		Breakdown:    breakdown,
+		JSONOutput:   format == models.FormatJSON,
+		Ascending:    ascending,
+		Models:       modelFilter,
+		NoDedupe:     noDedupe,
+		Verbose:      verbose,
+		Jobs:         jobs,
+		NoCache:      noCache,
+		DataDirs:     dataDirs,
+		Projects:     projects,
+		ServiceTiers: serviceTiers,
+		Format:       format,
+		Totals:       totals,
+		Template:     tmpl,
+		PricingFile:  pricingPath,
+	}
+
+	opts.CostMode, err = parseCostMode()
+	if err != nil {
+		return nil, err
+	}
+
+	if decimals < 0 || decimals > 9 {
+		return nil, fmt.Errorf("invalid decimals %d: must be between 0 and 9", decimals)
+	}
+	opts.Decimals = decimals
+
+	opts.Currency, opts.ExchangeRate, opts.RatesFile, err = loadCurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Location = time.Local
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+		opts.Location = loc
 	}
 
 	if since != "" {
-		t, err := time.Parse("20060102", since)
+		t, err := time.ParseInLocation("20060102", since, opts.Location)
 		if err != nil {
 			return nil, fmt.Errorf("invalid since date format: %w", err)
 		}
@@ -69,7 +109,7 @@ func parseOptions() (*models.ReportOptions, error) {
 	}
 
 	if until != "" {
-		t, err := time.Parse("20060102", until)
+		t, err := time.ParseInLocation("20060102", until, opts.Location)
 		if err != nil {
 			return nil, fmt.Errorf("invalid until date format: %w", err)
 		}
@@ -79,8 +119,64 @@ func parseOptions() (*models.ReportOptions, error) {
 	return opts, nil
 }
 
-func outputJSON(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
+func loadMessages(opts *models.ReportOptions) ([]models.Message, error) {
+	projectsDirs, err := parser.ResolveProjectsDirs(opts.DataDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	parseOpts := parser.ParseOptions{
+		NoDedupe: opts.NoDedupe,
+		Jobs:     opts.Jobs,
+		Since:    opts.Since,
+		Until:    opts.Until,
+	}
+	if !opts.NoCache {
+		parseOpts.CacheDir = parser.GetCacheDir()
+	}
+
+	messages, stats, err := parser.ParseJSONLFilesWithOptions(projectsDirs, parseOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing JSONL files: %w", err)
+	}
+
+	if opts.Verbose {
+		fmt.Fprintf(os.Stderr, "Reading %s\n", strings.Join(projectsDirs, ", "))
+		fmt.Fprintf(os.Stderr, "Parsed %d files (%d unchanged in cache), %d messages\n", stats.Files, stats.CachedFiles, len(messages))
+		if opts.Since != nil || opts.Until != nil {
+			fmt.Fprintf(os.Stderr, "Pruned %d files outside the date range (%d skipped, %d stopped early)\n",
+				stats.PrunedFiles(), stats.SkippedFiles, stats.TruncatedFiles)
+		}
+		if !opts.NoDedupe {
+			fmt.Fprintf(os.Stderr, "Removed %d duplicate entries\n", stats.Duplicates)
+		}
+	}
+
+	messages = parser.InLocation(messages, opts.Location)
+	messages = parser.FilterByDateRange(messages, opts.Since, opts.Until)
+	messages = parser.FilterByModels(messages, opts.Models)
+	messages = parser.FilterByProjects(messages, opts.Projects)
+	messages = parser.FilterByServiceTiers(messages, opts.ServiceTiers)
+
+	return messages, nil
+}
+
+// newCalculator returns the calculator that prices messages with the cost
+// mode, currency and time zone of opts.
+func newCalculator(opts *models.ReportOptions) calculator.Calculator {
+	return calculator.Calculator{CostMode: opts.CostMode, ExchangeRate: opts.ExchangeRate, Location: opts.Location}
+}
+
+// warnUnpriced is deferred by every report so that the warning follows it.
+func warnUnpriced(calc calculator.Calculator, messages []models.Message) {
+	display.ShowUnpricedWarning(calc.FindUnpricedModels(messages))
+}
+
+func parseCostMode() (models.CostMode, error) {
+	switch mode := models.CostMode(strings.ToLower(costMode)); mode {
+	case models.CostModeAuto, models.CostModeCalculate, models.CostModeDisplay:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid cost mode %q: must be auto, calculate or display", costMode)
+	}
 }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// parseFormat validates --format. --json is kept as a shorthand for
+// --format json, and md for markdown.
+func parseFormat() (models.OutputFormat, error) {
+	format := models.OutputFormat(strings.ToLower(outputFormat))
+	switch format {
+	case "md":
+		format = models.FormatMarkdown
+	case models.FormatTable, models.FormatJSON, models.FormatCSV, models.FormatTSV, models.FormatMarkdown, models.FormatHTML:
+	default:
+		return "", fmt.Errorf("invalid format %q: must be table, json, csv, tsv, markdown or html", outputFormat)
+	}
+
+	if jsonOutput {
+		if format != models.FormatTable && format != models.FormatJSON {
+			return "", fmt.Errorf("--json cannot be combined with --format %s", format)
+		}
+		format = models.FormatJSON
+	}
+	return format, nil
+}
+
+// loadTemplate returns the template given with --template or --template-file,
+// or "" if there is none. A template replaces the output format, so it cannot
+// be combined with a format other than table.
+func loadTemplate(format models.OutputFormat) (string, error) {
+	text := templateText
+	if templateFile != "" {
+		if templateText != "" {
+			return "", fmt.Errorf("--template and --template-file cannot be combined")
+		}
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template file: %w", err)
+		}
+		text = string(data)
+	}
+
+	if text != "" && format != models.FormatTable {
+		return "", fmt.Errorf("a template cannot be combined with --format %s", format)
+	}
+	return text, nil
+}
+
+// render writes report to standard output with the template, if any, or in
+// the format chosen by --format.
+func render(opts *models.ReportOptions, report display.Report) error {
+	costs := display.CostFormat{Currency: opts.Currency, Decimals: opts.Decimals}
+
+	var renderer display.Renderer
+	var err error
+	if opts.Template != "" {
+		renderer, err = display.NewTemplateRenderer(opts.Template, costs)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	} else {
+		renderer, err = display.NewRenderer(opts.Format, display.RenderOptions{
+			Totals:   opts.Totals,
+			Costs:    costs,
+			Metadata: jsonMetadata(opts),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return renderer.Render(os.Stdout, report)
+}
+
+// jsonMetadata describes the options a report was generated with for the JSON
+// envelope.
+func jsonMetadata(opts *models.ReportOptions) display.JSONMetadata {
+	meta := display.JSONMetadata{
+		GeneratedAt: time.Now().UTC(),
+		ToolVersion: toolVersion(),
+		Filters: display.JSONFilters{
+			Timezone:     "Local",
+			Models:       opts.Models,
+			Projects:     opts.Projects,
+			ServiceTiers: opts.ServiceTiers,
+		},
+		Pricing: display.JSONPricingSource{CostMode: string(opts.CostMode)},
+	}
+	if opts.Location != nil {
+		meta.Filters.Timezone = opts.Location.String()
+	}
+	if opts.Since != nil {
+		meta.Filters.Since = models.NewDate(opts.Since.Date())
+	}
+	if opts.Until != nil {
+		meta.Filters.Until = models.NewDate(opts.Until.Date())
+	}
+	if opts.PricingFile != "" {
+		meta.Pricing.File = &opts.PricingFile
+	}
+	if opts.RatesFile != "" {
+		meta.Pricing.RatesFile = &opts.RatesFile
+	}
+	return meta
+}
@@ -1,12 +1,8 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
-	"github.com/t-ishitsuka/claude-usage-go/internal/calculator"
 	"github.com/t-ishitsuka/claude-usage-go/internal/display"
-	"github.com/t-ishitsuka/claude-usage-go/internal/parser"
 )
 
 var monthlyCmd = &cobra.Command{
@@ -26,24 +22,14 @@ func runMonthly(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	projectsDir := parser.GetClaudeProjectsDir()
-	messages, err := parser.ParseJSONLFiles(projectsDir)
+	messages, err := loadMessages(opts)
 	if err != nil {
-		return fmt.Errorf("error parsing JSONL files: %w", err)
-	}
-
-	messages = parser.FilterByDateRange(messages, opts.Since, opts.Until)
-	messages = parser.FilterByModels(messages, opts.Models)
-
-	monthlyUsage := calculator.AggregateMonthly(messages)
-
-	if opts.JSONOutput {
-		return outputJSON(monthlyUsage)
+		return err
 	}
+	calc := newCalculator(opts)
+	defer warnUnpriced(calc, messages)
 
-	if opts.Breakdown {
-		return display.ShowMonthlyWithBreakdown(monthlyUsage, messages, opts.Ascending)
-	}
+	monthlyUsage := calc.AggregateMonthly(messages)
 
-	return display.ShowMonthly(monthlyUsage, opts.Ascending)
+	return render(opts, display.MonthlyReport(monthlyUsage, messages, calc, opts.Ascending, opts.Breakdown))
 }
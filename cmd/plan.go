@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+var (
+	planName   string
+	planPrice  float64
+	blockLimit float64
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Compare API-equivalent cost with subscription plans",
+	Long: `Compare the monthly API-equivalent cost of your usage with the price of a
+Claude subscription plan (Pro, Max 5x or Max 20x). Shows how much value the plan
+gave, the day it paid for itself, and which option would have been cheapest.
+
+Subscription usage limits are not published as costs. Pass --block-limit with
+the API-equivalent cost in USD that a Pro plan allows per 5-hour block to rule
+out plans whose limits your busiest blocks would have exceeded. Like
+--plan-price, it is converted to --currency at each month's rate.`,
+	RunE: runPlan,
+}
+
+func init() {
+	planCmd.Flags().StringVar(&planName, "plan", "max5x", "Subscription plan to compare with (pro, max5x or max20x)")
+	planCmd.Flags().Float64Var(&planPrice, "plan-price", 0, "Monthly price of the plan in USD (default: the plan's list price)")
+	planCmd.Flags().Float64Var(&blockLimit, "block-limit", 0, "API-equivalent cost in USD that a Pro plan allows per 5-hour block (default: limits ignored)")
+	rootCmd.AddCommand(planCmd)
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	opts, err := parseOptions()
+	if err != nil {
+		return err
+	}
+
+	plan, ok := models.FindSubscriptionPlan(planName)
+	if !ok {
+		return fmt.Errorf("invalid plan %q: must be pro, max5x or max20x", planName)
+	}
+	if planPrice < 0 || blockLimit < 0 {
+		return fmt.Errorf("--plan-price and --block-limit must not be negative")
+	}
+	if planPrice > 0 {
+		plan.PriceUSD = models.USD(planPrice)
+	}
+
+	messages, err := loadMessages(opts)
+	if err != nil {
+		return err
+	}
+	calc := newCalculator(opts)
+	defer warnUnpriced(calc, messages)
+
+	comparison := calc.ComparePlans(messages, plan, models.USD(blockLimit))
+
+	return render(opts, display.PlanReport(comparison, opts.Ascending))
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+var pricingCmd = &cobra.Command{
+	Use:   "pricing",
+	Short: "Inspect model pricing",
+	Long:  `Inspect the model prices used to estimate costs.`,
+}
+
+var pricingListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List effective model prices",
+	Long: `List the effective price of every model, and whether it comes from the
+built-in table or from a pricing file.`,
+	RunE: runPricingList,
+}
+
+func init() {
+	pricingCmd.AddCommand(pricingListCmd)
+	rootCmd.AddCommand(pricingCmd)
+}
+
+func runPricingList(cmd *cobra.Command, args []string) error {
+	pricingPath, err := loadPricing()
+	if err != nil {
+		return err
+	}
+
+	mode, err := parseCostMode()
+	if err != nil {
+		return err
+	}
+
+	format, err := parseFormat()
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := loadTemplate(format)
+	if err != nil {
+		return err
+	}
+
+	opts := &models.ReportOptions{
+		Format:      format,
+		Totals:      totals,
+		Template:    tmpl,
+		CostMode:    mode,
+		PricingFile: pricingPath,
+	}
+	return render(opts, display.PricingReport(models.EffectivePricing()))
+}
+
+// loadPricing merges the pricing file given with --pricing-file, or else the
+// one found in the config dir, over the built-in prices.
+func loadPricing() (string, error) {
+	path := pricingFile
+	if path == "" {
+		path = models.FindPricingFile(models.GetPricingConfigDir())
+		if path == "" {
+			return "", nil
+		}
+	}
+
+	file, err := models.LoadPricingFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error loading pricing file: %w", err)
+	}
+
+	models.ApplyPricingFile(file, path)
+	return path, nil
+}
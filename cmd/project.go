@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Show project usage report",
+	Long:  `Display token usage and costs aggregated by project.`,
+	RunE:  runProject,
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+}
+
+func runProject(cmd *cobra.Command, args []string) error {
+	opts, err := parseOptions()
+	if err != nil {
+		return err
+	}
+
+	messages, err := loadMessages(opts)
+	if err != nil {
+		return err
+	}
+	calc := newCalculator(opts)
+	defer warnUnpriced(calc, messages)
+
+	projectUsage := calc.AggregateByProject(messages)
+
+	return render(opts, display.ProjectReport(projectUsage, messages, calc, opts.Ascending, opts.Breakdown))
+}
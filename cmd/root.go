@@ -5,15 +5,34 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
 )
 
 var (
-	since       string
-	until       string
-	breakdown   bool
-	jsonOutput  bool
-	ascending   bool
-	modelFilter []string
+	since        string
+	until        string
+	breakdown    bool
+	jsonOutput   bool
+	ascending    bool
+	modelFilter  []string
+	noDedupe     bool
+	verbose      bool
+	jobs         int
+	noCache      bool
+	dataDirs     []string
+	projects     []string
+	serviceTiers []string
+	timezone     string
+	pricingFile  string
+	costMode     string
+	decimals     int
+	currencyCode string
+	ratesFile    string
+	outputFormat string
+	totals       bool
+	templateText string
+	templateFile string
 )
 
 var rootCmd = &cobra.Command{
@@ -33,10 +52,28 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.Version = toolVersion()
 	rootCmd.PersistentFlags().StringVar(&since, "since", "", "Start date (YYYYMMDD format)")
 	rootCmd.PersistentFlags().StringVar(&until, "until", "", "End date (YYYYMMDD format)")
+	rootCmd.PersistentFlags().StringVar(&timezone, "timezone", "", "IANA time zone for dates and grouping, e.g. Asia/Tokyo (default local time)")
 	rootCmd.PersistentFlags().BoolVar(&breakdown, "breakdown", false, "Show model breakdown")
-	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output as JSON (same as --format json)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", string(models.FormatTable), "Output format: table, json, csv, tsv, markdown or html")
+	rootCmd.PersistentFlags().BoolVar(&totals, "totals", false, "Append a TOTAL row to CSV and TSV output")
+	rootCmd.PersistentFlags().StringVar(&templateText, "template", "", "Go text/template to write the report with instead of a format")
+	rootCmd.PersistentFlags().StringVar(&templateFile, "template-file", "", "File containing a Go text/template to write the report with")
 	rootCmd.PersistentFlags().BoolVar(&ascending, "asc", false, "Sort in descending order (newest first)")
 	rootCmd.PersistentFlags().StringSliceVar(&modelFilter, "models", []string{}, "Filter by models")
+	rootCmd.PersistentFlags().StringSliceVar(&projects, "project", []string{}, "Filter by project path or name")
+	rootCmd.PersistentFlags().StringSliceVar(&serviceTiers, "service-tier", []string{}, "Filter by service tier (standard, batch or priority)")
+	rootCmd.PersistentFlags().StringVar(&pricingFile, "pricing-file", "", "JSON or YAML pricing file merged over the built-in prices (default: pricing.json/.yaml in the config dir)")
+	rootCmd.PersistentFlags().StringVar(&costMode, "cost-mode", "auto", "Cost source: auto (recorded costUSD when present), calculate (always from pricing) or display (recorded costUSD only)")
+	rootCmd.PersistentFlags().StringVar(&currencyCode, "currency", models.BaseCurrency, "Currency to report costs in, e.g. JPY or EUR (converted with the rates file)")
+	rootCmd.PersistentFlags().StringVar(&ratesFile, "rates-file", "", "JSON or YAML exchange rate file (default: rates.json/.yaml in the config dir)")
+	rootCmd.PersistentFlags().IntVar(&decimals, "decimals", display.DefaultCostDecimals, "Number of decimal places costs are rounded to in tables (0-9)")
+	rootCmd.PersistentFlags().BoolVar(&noDedupe, "no-dedupe", false, "Keep duplicate entries that share a message ID and request ID")
+	rootCmd.PersistentFlags().StringSliceVar(&dataDirs, "data-dir", []string{}, "Claude config or projects directory to read (repeatable; defaults to CLAUDE_CONFIG_DIR, ~/.config/claude and ~/.claude)")
+	rootCmd.PersistentFlags().IntVarP(&jobs, "jobs", "j", 0, "Number of files to parse concurrently (default GOMAXPROCS)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Parse every file from scratch without using the parse cache")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print parsing statistics to stderr")
 }
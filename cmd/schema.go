@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of JSON output",
+	Long: `Print the JSON Schema (draft 2020-12) that --json and --format json output
+conforms to. The schema_version field of the output is bumped whenever a field
+is removed or changes meaning; new fields may be added without a bump.`,
+	Args: cobra.NoArgs,
+	RunE: runSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	schema, err := display.JSONSchema()
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(schema))
+	return nil
+}
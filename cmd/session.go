@@ -1,12 +1,8 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
-	"github.com/t-ishitsuka/claude-usage-go/internal/calculator"
 	"github.com/t-ishitsuka/claude-usage-go/internal/display"
-	"github.com/t-ishitsuka/claude-usage-go/internal/parser"
 )
 
 var sessionCmd = &cobra.Command{
@@ -26,24 +22,14 @@ func runSession(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	projectsDir := parser.GetClaudeProjectsDir()
-	messages, err := parser.ParseJSONLFiles(projectsDir)
+	messages, err := loadMessages(opts)
 	if err != nil {
-		return fmt.Errorf("error parsing JSONL files: %w", err)
-	}
-
-	messages = parser.FilterByDateRange(messages, opts.Since, opts.Until)
-	messages = parser.FilterByModels(messages, opts.Models)
-
-	sessionUsage := calculator.AggregateBySession(messages)
-
-	if opts.JSONOutput {
-		return outputJSON(sessionUsage)
+		return err
 	}
+	calc := newCalculator(opts)
+	defer warnUnpriced(calc, messages)
 
-	if opts.Breakdown {
-		return display.ShowSessionWithBreakdown(sessionUsage, messages, opts.Ascending)
-	}
+	sessionUsage := calc.AggregateBySession(messages)
 
-	return display.ShowSession(sessionUsage, opts.Ascending)
+	return render(opts, display.SessionReport(sessionUsage, messages, calc, opts.Ascending, opts.Breakdown))
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+)
+
+var tierCmd = &cobra.Command{
+	Use:   "tier",
+	Short: "Show usage report by service tier",
+	Long: `Display token usage and costs aggregated by service tier (standard, batch
+or priority).`,
+	RunE: runTier,
+}
+
+func init() {
+	rootCmd.AddCommand(tierCmd)
+}
+
+func runTier(cmd *cobra.Command, args []string) error {
+	opts, err := parseOptions()
+	if err != nil {
+		return err
+	}
+
+	messages, err := loadMessages(opts)
+	if err != nil {
+		return err
+	}
+	calc := newCalculator(opts)
+	defer warnUnpriced(calc, messages)
+
+	tierUsage := calc.AggregateByServiceTier(messages)
+
+	return render(opts, display.ServiceTierReport(tierUsage, messages, calc, opts.Breakdown))
+}
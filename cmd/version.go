@@ -0,0 +1,19 @@
+package cmd
+
+import "runtime/debug"
+
+// version is set at build time with
+// -ldflags "-X github.com/t-ishitsuka/claude-usage-go/cmd.version=v1.2.3".
+var version = "dev"
+
+// toolVersion returns the version set at build time, or else the module
+// version recorded by go install.
+func toolVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return version
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/t-ishitsuka/claude-usage-go/internal/display"
+)
+
+var weekStart string
+
+var weeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Show weekly usage report",
+	Long: `Display token usage and costs aggregated by week.
+Weeks use ISO week numbering and start on Monday unless --week-start is given.`,
+	RunE: runWeekly,
+}
+
+func init() {
+	weeklyCmd.Flags().StringVar(&weekStart, "week-start", "monday", "First day of the week (monday or sunday)")
+	rootCmd.AddCommand(weeklyCmd)
+}
+
+func runWeekly(cmd *cobra.Command, args []string) error {
+	opts, err := parseOptions()
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(weekStart) {
+	case "monday":
+		opts.WeekStart = time.Monday
+	case "sunday":
+		opts.WeekStart = time.Sunday
+	default:
+		return fmt.Errorf("invalid week start %q: must be monday or sunday", weekStart)
+	}
+
+	messages, err := loadMessages(opts)
+	if err != nil {
+		return err
+	}
+	calc := newCalculator(opts)
+	defer warnUnpriced(calc, messages)
+
+	weeklyUsage := calc.AggregateWeekly(messages, opts.WeekStart)
+
+	return render(opts, display.WeeklyReport(weeklyUsage, messages, calc, opts.Ascending, opts.Breakdown))
+}
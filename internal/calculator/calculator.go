@@ -1,28 +1,101 @@
 package calculator
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
 	"github.com/t-ishitsuka/claude-usage-go/internal/models"
 )
 
-func CalculateCost(usage models.TokenUsage, model string) float64 {
-	pricing, ok := models.ModelPricing[model]
+// Calculator aggregates and prices messages. The zero value uses
+// models.CostModeAuto and reports costs in US dollars.
+type Calculator struct {
+	// CostMode selects how messages are priced
+	CostMode models.CostMode
+	// ExchangeRate converts costs to the report currency; nil means US dollars
+	ExchangeRate *models.ExchangeRate
+	// Location is the time zone of the calendar months that ComparePlans
+	// walks, which should be the one message timestamps are in; nil means UTC
+	Location *time.Location
+}
+
+// MessageCost returns the cost of msg according to the cost mode, in the
+// report currency.
+func (c Calculator) MessageCost(msg models.Message) models.Money {
+	return c.convert(c.messageCostUSD(msg), msg.Timestamp)
+}
+
+func (c Calculator) messageCostUSD(msg models.Message) models.Money {
+	switch c.CostMode {
+	case models.CostModeCalculate:
+		return calculatedCost(msg)
+	case models.CostModeDisplay:
+		if msg.RecordedCostUSD == nil {
+			return 0
+		}
+		return *msg.RecordedCostUSD
+	}
+
+	if msg.RecordedCostUSD != nil {
+		return *msg.RecordedCostUSD
+	}
+	return calculatedCost(msg)
+}
+
+// usesRecordedCost reports whether msg is priced by its recorded cost rather
+// than from pricing.
+func (c Calculator) usesRecordedCost(msg models.Message) bool {
+	switch c.CostMode {
+	case models.CostModeCalculate:
+		return false
+	case models.CostModeDisplay:
+		return true
+	}
+	return msg.RecordedCostUSD != nil
+}
+
+// calculatedCost prices msg at the rates in effect at its timestamp, scaled by
+// the multiplier of its service tier.
+func calculatedCost(msg models.Message) models.Money {
+	cost := CalculateCostAt(msg.TokenUsage, msg.Model, msg.Timestamp)
+	return cost.Mul(models.ServiceTierMultiplier(msg.ServiceTier))
+}
+
+// CalculateCost prices usage at the current rates of model. usage is treated
+// as a single request when deciding whether long context rates apply.
+func CalculateCost(usage models.TokenUsage, model string) models.Money {
+	pricing, ok := models.GetCurrentPricing(model)
+	if !ok {
+		return 0
+	}
+	return costWithPricing(usage, pricing)
+}
+
+// CalculateCostAt prices usage at the rates of model that were in effect at t.
+func CalculateCostAt(usage models.TokenUsage, model string, t time.Time) models.Money {
+	pricing, ok := models.GetPricing(model, t)
 	if !ok {
 		return 0
 	}
+	return costWithPricing(usage, pricing)
+}
+
+func costWithPricing(usage models.TokenUsage, pricing models.Pricing) models.Money {
+	pricing = pricing.ForRequest(usage)
 
-	cost := 0.0
-	cost += float64(usage.InputTokens) / 1_000_000 * pricing.InputPer1M
-	cost += float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPer1M
-	cost += float64(usage.CacheCreateTokens) / 1_000_000 * pricing.CacheCreatePer1M
-	cost += float64(usage.CacheReadTokens) / 1_000_000 * pricing.CacheReadPer1M
+	var cost models.Money
+	cost += models.Money(usage.InputTokens) * models.PerToken(pricing.InputPer1M)
+	cost += models.Money(usage.OutputTokens) * models.PerToken(pricing.OutputPer1M)
+	cost += models.Money(usage.CacheCreateTokens) * models.PerToken(pricing.CacheCreatePer1M)
+	cost += models.Money(usage.CacheCreate1hTokens) * models.PerToken(pricing.CacheCreate1hRate())
+	cost += models.Money(usage.CacheReadTokens) * models.PerToken(pricing.CacheReadPer1M)
+	cost += models.Money(usage.WebSearchRequests) * models.USD(pricing.WebSearchRate()/1_000)
 
 	return cost
 }
 
-func AggregateDaily(messages []models.Message) []models.DailyUsage {
+func (c Calculator) AggregateDaily(messages []models.Message) []models.DailyUsage {
 	dailyMap := make(map[string]*models.DailyUsage)
 
 	for _, msg := range messages {
@@ -30,19 +103,16 @@ func AggregateDaily(messages []models.Message) []models.DailyUsage {
 
 		if _, exists := dailyMap[dateKey]; !exists {
 			dailyMap[dateKey] = &models.DailyUsage{
-				Date:   msg.Timestamp.Truncate(24 * time.Hour),
+				Date:   startOfDay(msg.Timestamp),
 				Models: make([]string, 0),
 			}
 		}
 
 		daily := dailyMap[dateKey]
-		daily.TokenUsage.InputTokens += msg.TokenUsage.InputTokens
-		daily.TokenUsage.OutputTokens += msg.TokenUsage.OutputTokens
-		daily.TokenUsage.CacheCreateTokens += msg.TokenUsage.CacheCreateTokens
-		daily.TokenUsage.CacheReadTokens += msg.TokenUsage.CacheReadTokens
+		daily.TokenUsage.Add(msg.TokenUsage)
 
-		msg.EstimatedCostUSD = CalculateCost(msg.TokenUsage, msg.Model)
-		daily.CostUSD += msg.EstimatedCostUSD
+		msg.EstimatedCost = c.MessageCost(msg)
+		daily.Cost += msg.EstimatedCost
 
 		if !contains(daily.Models, msg.Model) {
 			daily.Models = append(daily.Models, msg.Model)
@@ -61,7 +131,51 @@ func AggregateDaily(messages []models.Message) []models.DailyUsage {
 	return result
 }
 
-func AggregateMonthly(messages []models.Message) []models.MonthlyUsage {
+// AggregateWeekly groups messages into weeks starting on weekStart. With
+// time.Monday this is ISO week numbering.
+func (c Calculator) AggregateWeekly(messages []models.Message, weekStart time.Weekday) []models.WeeklyUsage {
+	weeklyMap := make(map[string]*models.WeeklyUsage)
+
+	for _, msg := range messages {
+		start := startOfWeek(msg.Timestamp, weekStart)
+		weekKey := start.Format("2006-01-02")
+
+		if _, exists := weeklyMap[weekKey]; !exists {
+			// The Monday of the week decides the ISO year and week number
+			monday := start.AddDate(0, 0, (int(time.Monday)-int(weekStart)+7)%7)
+			year, week := monday.ISOWeek()
+			weeklyMap[weekKey] = &models.WeeklyUsage{
+				Year:      year,
+				Week:      week,
+				StartDate: start,
+				Models:    make([]string, 0),
+			}
+		}
+
+		weekly := weeklyMap[weekKey]
+		weekly.TokenUsage.Add(msg.TokenUsage)
+
+		msg.EstimatedCost = c.MessageCost(msg)
+		weekly.Cost += msg.EstimatedCost
+
+		if !contains(weekly.Models, msg.Model) {
+			weekly.Models = append(weekly.Models, msg.Model)
+		}
+	}
+
+	var result []models.WeeklyUsage
+	for _, weekly := range weeklyMap {
+		result = append(result, *weekly)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartDate.Before(result[j].StartDate)
+	})
+
+	return result
+}
+
+func (c Calculator) AggregateMonthly(messages []models.Message) []models.MonthlyUsage {
 	monthlyMap := make(map[string]*models.MonthlyUsage)
 
 	for _, msg := range messages {
@@ -76,13 +190,10 @@ func AggregateMonthly(messages []models.Message) []models.MonthlyUsage {
 		}
 
 		monthly := monthlyMap[monthKey]
-		monthly.TokenUsage.InputTokens += msg.TokenUsage.InputTokens
-		monthly.TokenUsage.OutputTokens += msg.TokenUsage.OutputTokens
-		monthly.TokenUsage.CacheCreateTokens += msg.TokenUsage.CacheCreateTokens
-		monthly.TokenUsage.CacheReadTokens += msg.TokenUsage.CacheReadTokens
+		monthly.TokenUsage.Add(msg.TokenUsage)
 
-		msg.EstimatedCostUSD = CalculateCost(msg.TokenUsage, msg.Model)
-		monthly.CostUSD += msg.EstimatedCostUSD
+		msg.EstimatedCost = c.MessageCost(msg)
+		monthly.Cost += msg.EstimatedCost
 
 		if !contains(monthly.Models, msg.Model) {
 			monthly.Models = append(monthly.Models, msg.Model)
@@ -104,7 +215,7 @@ func AggregateMonthly(messages []models.Message) []models.MonthlyUsage {
 	return result
 }
 
-func AggregateBySession(messages []models.Message) []models.SessionUsage {
+func (c Calculator) AggregateBySession(messages []models.Message) []models.SessionUsage {
 	sessionMap := make(map[string]*models.SessionUsage)
 
 	for _, msg := range messages {
@@ -118,13 +229,10 @@ func AggregateBySession(messages []models.Message) []models.SessionUsage {
 		}
 
 		session := sessionMap[msg.SessionID]
-		session.TokenUsage.InputTokens += msg.TokenUsage.InputTokens
-		session.TokenUsage.OutputTokens += msg.TokenUsage.OutputTokens
-		session.TokenUsage.CacheCreateTokens += msg.TokenUsage.CacheCreateTokens
-		session.TokenUsage.CacheReadTokens += msg.TokenUsage.CacheReadTokens
+		session.TokenUsage.Add(msg.TokenUsage)
 
-		msg.EstimatedCostUSD = CalculateCost(msg.TokenUsage, msg.Model)
-		session.CostUSD += msg.EstimatedCostUSD
+		msg.EstimatedCost = c.MessageCost(msg)
+		session.Cost += msg.EstimatedCost
 
 		if msg.Timestamp.Before(session.StartTime) {
 			session.StartTime = msg.Timestamp
@@ -150,22 +258,186 @@ func AggregateBySession(messages []models.Message) []models.SessionUsage {
 	return result
 }
 
-func AggregateByModel(messages []models.Message) []models.ModelBreakdown {
+func (c Calculator) AggregateByProject(messages []models.Message) []models.ProjectUsage {
+	projectMap := make(map[string]*models.ProjectUsage)
+
+	for _, msg := range messages {
+		if _, exists := projectMap[msg.Project]; !exists {
+			projectMap[msg.Project] = &models.ProjectUsage{
+				Project: msg.Project,
+				Models:  make([]string, 0),
+			}
+		}
+
+		project := projectMap[msg.Project]
+		project.TokenUsage.Add(msg.TokenUsage)
+
+		msg.EstimatedCost = c.MessageCost(msg)
+		project.Cost += msg.EstimatedCost
+
+		if !contains(project.Models, msg.Model) {
+			project.Models = append(project.Models, msg.Model)
+		}
+	}
+
+	var result []models.ProjectUsage
+	for _, project := range projectMap {
+		result = append(result, *project)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Project < result[j].Project
+	})
+
+	return result
+}
+
+const BlockDuration = 5 * time.Hour
+
+// AggregateByServiceTier groups messages by service tier, in the order
+// standard, batch, priority, then any other tier by name.
+func (c Calculator) AggregateByServiceTier(messages []models.Message) []models.ServiceTierUsage {
+	tierMap := make(map[string]*models.ServiceTierUsage)
+
+	for _, msg := range messages {
+		tier := models.NormalizeServiceTier(msg.ServiceTier)
+		if _, exists := tierMap[tier]; !exists {
+			tierMap[tier] = &models.ServiceTierUsage{
+				ServiceTier: tier,
+				Models:      make([]string, 0),
+			}
+		}
+
+		usage := tierMap[tier]
+		usage.TokenUsage.Add(msg.TokenUsage)
+
+		msg.EstimatedCost = c.MessageCost(msg)
+		usage.Cost += msg.EstimatedCost
+
+		if !contains(usage.Models, msg.Model) {
+			usage.Models = append(usage.Models, msg.Model)
+		}
+	}
+
+	var result []models.ServiceTierUsage
+	for _, usage := range tierMap {
+		result = append(result, *usage)
+	}
+
+	order := map[string]int{models.ServiceTierStandard: 0, models.ServiceTierBatch: 1, models.ServiceTierPriority: 2}
+	sort.Slice(result, func(i, j int) bool {
+		oi, iKnown := order[result[i].ServiceTier]
+		oj, jKnown := order[result[j].ServiceTier]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return result[i].ServiceTier < result[j].ServiceTier
+	})
+
+	return result
+}
+
+// AggregateBlocks groups messages into 5-hour billing blocks. A block starts
+// at the hour of the first message after the previous block ended, and the
+// block containing now is marked active and given a projection. Hours are
+// taken in the location of the message timestamps.
+func (c Calculator) AggregateBlocks(messages []models.Message, now time.Time) []models.BlockUsage {
+	sorted := make([]models.Message, len(messages))
+	copy(sorted, messages)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var result []models.BlockUsage
+	var block *models.BlockUsage
+
+	for _, msg := range sorted {
+		if block == nil || !msg.Timestamp.Before(block.EndTime) {
+			if block != nil {
+				result = append(result, *block)
+			}
+			start := startOfHour(msg.Timestamp)
+			block = &models.BlockUsage{
+				StartTime: start,
+				EndTime:   start.Add(BlockDuration),
+				Models:    make([]string, 0),
+			}
+		}
+
+		block.TokenUsage.Add(msg.TokenUsage)
+
+		msg.EstimatedCost = c.MessageCost(msg)
+		block.Cost += msg.EstimatedCost
+		block.LastActivity = msg.Timestamp
+
+		if !contains(block.Models, msg.Model) {
+			block.Models = append(block.Models, msg.Model)
+		}
+	}
+
+	if block != nil {
+		if !now.Before(block.StartTime) && now.Before(block.EndTime) {
+			block.IsActive = true
+			block.Projection = projectBlock(*block, now)
+		}
+		result = append(result, *block)
+	}
+
+	return result
+}
+
+func projectBlock(block models.BlockUsage, now time.Time) *models.BlockProjection {
+	projection := &models.BlockProjection{
+		Elapsed:   now.Sub(block.StartTime),
+		Remaining: block.EndTime.Sub(now),
+	}
+
+	minutes := projection.Elapsed.Minutes()
+	if minutes <= 0 {
+		projection.ProjectedTokens = block.TokenUsage.Total()
+		projection.ProjectedCost = block.Cost
+		return projection
+	}
+
+	projection.TokensPerMinute = float64(block.TokenUsage.Total()) / minutes
+	projection.CostPerHour = models.USD(block.Cost.Float64() / projection.Elapsed.Hours())
+	projection.ProjectedTokens = block.TokenUsage.Total() + int(projection.TokensPerMinute*projection.Remaining.Minutes())
+	projection.ProjectedCost = block.Cost + models.USD(projection.CostPerHour.Float64()*projection.Remaining.Hours())
+
+	return projection
+}
+
+// AggregateByModel breaks usage down by model and, for models whose price
+// changed, by the price period that applied. Requests billed at long context
+// rates and requests of each service tier are kept apart from the rest.
+func (c Calculator) AggregateByModel(messages []models.Message) []models.ModelBreakdown {
 	modelMap := make(map[string]*models.ModelBreakdown)
 
 	for _, msg := range messages {
-		if _, exists := modelMap[msg.Model]; !exists {
-			modelMap[msg.Model] = &models.ModelBreakdown{
-				Model: msg.Model,
+		var period string
+		var longContext bool
+		if pricing, ok := models.GetPricing(msg.Model, msg.Timestamp); ok {
+			period = pricing.Period()
+			longContext = pricing.IsLongContext(msg.TokenUsage)
+		}
+
+		tier := models.NormalizeServiceTier(msg.ServiceTier)
+		key := fmt.Sprintf("%s|%s|%t|%s", msg.Model, period, longContext, tier)
+		if _, exists := modelMap[key]; !exists {
+			modelMap[key] = &models.ModelBreakdown{
+				Model:       msg.Model,
+				PricePeriod: period,
+				LongContext: longContext,
+				ServiceTier: tier,
 			}
 		}
 
-		breakdown := modelMap[msg.Model]
-		breakdown.TokenUsage.InputTokens += msg.TokenUsage.InputTokens
-		breakdown.TokenUsage.OutputTokens += msg.TokenUsage.OutputTokens
-		breakdown.TokenUsage.CacheCreateTokens += msg.TokenUsage.CacheCreateTokens
-		breakdown.TokenUsage.CacheReadTokens += msg.TokenUsage.CacheReadTokens
-		breakdown.CostUSD += CalculateCost(msg.TokenUsage, msg.Model)
+		breakdown := modelMap[key]
+		breakdown.TokenUsage.Add(msg.TokenUsage)
+		breakdown.Cost += c.MessageCost(msg)
 	}
 
 	var result []models.ModelBreakdown
@@ -174,12 +446,138 @@ func AggregateByModel(messages []models.Message) []models.ModelBreakdown {
 	}
 
 	sort.Slice(result, func(i, j int) bool {
-		return result[i].CostUSD > result[j].CostUSD
+		a, b := result[i], result[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		if a.PricePeriod != b.PricePeriod {
+			return a.PricePeriod < b.PricePeriod
+		}
+		if a.ServiceTier != b.ServiceTier {
+			return a.ServiceTier < b.ServiceTier
+		}
+		return !a.LongContext && b.LongContext
+	})
+
+	return result
+}
+
+// startOfDay and startOfHour truncate in t's own location; time.Truncate
+// would round relative to UTC and shift dates in other time zones.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+func startOfHour(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+// FindUnpricedModels lists the models that have no price of their own, with
+// the tokens they account for, largest first: those that could not be priced
+// and those priced as another snapshot of their family and version. Messages
+// priced by their recorded cost under the current cost mode are skipped.
+func (c Calculator) FindUnpricedModels(messages []models.Message) []models.UnpricedModel {
+	unpricedMap := make(map[string]*models.UnpricedModel)
+
+	for _, msg := range messages {
+		if c.usesRecordedCost(msg) {
+			continue
+		}
+		pricing, ok := models.GetPricing(msg.Model, msg.Timestamp)
+		fallback, isFallback := models.FallbackModel(msg.Model)
+		if ok && !isFallback {
+			continue
+		}
+
+		if _, exists := unpricedMap[msg.Model]; !exists {
+			unpricedMap[msg.Model] = &models.UnpricedModel{Model: msg.Model}
+			if isFallback {
+				unpricedMap[msg.Model].PricedAs = fallback
+				unpricedMap[msg.Model].Pricing = pricing
+			}
+		}
+
+		unpriced := unpricedMap[msg.Model]
+		unpriced.Messages++
+		unpriced.TokenUsage.Add(msg.TokenUsage)
+	}
+
+	var result []models.UnpricedModel
+	for _, unpriced := range unpricedMap {
+		result = append(result, *unpriced)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TokenUsage.Total() != result[j].TokenUsage.Total() {
+			return result[i].TokenUsage.Total() > result[j].TokenUsage.Total()
+		}
+		return result[i].Model < result[j].Model
+	})
+
+	return result
+}
+
+// AuditCosts compares the recorded cost of each message that has one with its
+// calculated cost, per model, largest absolute difference first. Both are in
+// the report currency.
+func (c Calculator) AuditCosts(messages []models.Message) []models.CostAudit {
+	auditMap := make(map[string]*models.CostAudit)
+
+	for _, msg := range messages {
+		if msg.RecordedCostUSD == nil {
+			continue
+		}
+
+		if _, exists := auditMap[msg.Model]; !exists {
+			auditMap[msg.Model] = &models.CostAudit{Model: msg.Model}
+		}
+
+		audit := auditMap[msg.Model]
+		audit.Messages++
+		audit.RecordedCost += c.convert(*msg.RecordedCostUSD, msg.Timestamp)
+		audit.CalculatedCost += c.convert(calculatedCost(msg), msg.Timestamp)
+	}
+
+	var result []models.CostAudit
+	for _, audit := range auditMap {
+		audit.Difference = audit.CalculatedCost - audit.RecordedCost
+		result = append(result, *audit)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		di, dj := result[i].Difference.Abs(), result[j].Difference.Abs()
+		if di != dj {
+			return di > dj
+		}
+		return result[i].Model < result[j].Model
 	})
 
 	return result
 }
 
+// convert converts a cost in US dollars at the rate for the month of t.
+func (c Calculator) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+func (c Calculator) convert(usd models.Money, t time.Time) models.Money {
+	if c.ExchangeRate == nil {
+		return usd
+	}
+	return c.ExchangeRate.Convert(usd, t)
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -1,6 +1,7 @@
 package calculator
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -12,7 +13,7 @@ func TestCalculateCost(t *testing.T) {
 		name     string
 		usage    models.TokenUsage
 		model    string
-		expected float64
+		expected models.Money
 	}{
 		{
 			name: "Opus 4 with all token types",
@@ -23,7 +24,7 @@ func TestCalculateCost(t *testing.T) {
 				CacheReadTokens:   1000000,
 			},
 			model:    "claude-opus-4-20250514",
-			expected: 15.0 + 75.0 + 18.75 + 1.5, // 110.25
+			expected: models.USD(15.0 + 75.0 + 18.75 + 1.5), // 110.25
 		},
 		{
 			name: "Sonnet 4 with partial tokens",
@@ -32,7 +33,43 @@ func TestCalculateCost(t *testing.T) {
 				OutputTokens: 500000,
 			},
 			model:    "claude-sonnet-4-20250514",
-			expected: 1.5 + 7.5, // 9.0
+			expected: models.USD(3.0 + 11.25), // long context rates above 200K
+		},
+		{
+			name: "Sonnet 4 below the long context threshold",
+			usage: models.TokenUsage{
+				InputTokens:  125000,
+				OutputTokens: 500000,
+			},
+			model:    "claude-sonnet-4-20250514",
+			expected: models.USD(0.375 + 7.5), // 7.875
+		},
+		{
+			name: "Sonnet 4 with 5-minute and 1-hour cache writes",
+			usage: models.TokenUsage{
+				CacheCreateTokens:   1000000,
+				CacheCreate1hTokens: 1000000,
+			},
+			model:    "claude-sonnet-4-20250514",
+			expected: models.USD(7.5 + 12.0), // long context rates above 200K
+		},
+		{
+			name: "Sonnet 4 with cache writes below the long context threshold",
+			usage: models.TokenUsage{
+				CacheCreateTokens:   62500,
+				CacheCreate1hTokens: 62500,
+			},
+			model:    "claude-sonnet-4-20250514",
+			expected: models.USD(0.234375 + 0.375), // 1.25x and 2x input
+		},
+		{
+			name: "Web searches are billed per 1,000",
+			usage: models.TokenUsage{
+				InputTokens:       125000,
+				WebSearchRequests: 50,
+			},
+			model:    "claude-sonnet-4-20250514",
+			expected: models.USD(0.375 + 0.5),
 		},
 		{
 			name: "Unknown model returns 0",
@@ -42,6 +79,15 @@ func TestCalculateCost(t *testing.T) {
 			model:    "unknown-model",
 			expected: 0,
 		},
+		{
+			name: "Synthetic messages are free, web searches included",
+			usage: models.TokenUsage{
+				InputTokens:       1000,
+				WebSearchRequests: 10,
+			},
+			model:    models.SyntheticModel,
+			expected: 0,
+		},
 		{
 			name: "Zero tokens",
 			usage: models.TokenUsage{
@@ -97,7 +143,7 @@ func TestAggregateDaily(t *testing.T) {
 		},
 	}
 
-	result := AggregateDaily(messages)
+	result := Calculator{}.AggregateDaily(messages)
 
 	if len(result) != 2 {
 		t.Fatalf("Expected 2 daily aggregations, got %d", len(result))
@@ -161,7 +207,7 @@ func TestAggregateMonthly(t *testing.T) {
 		},
 	}
 
-	result := AggregateMonthly(messages)
+	result := Calculator{}.AggregateMonthly(messages)
 
 	if len(result) != 2 {
 		t.Fatalf("Expected 2 monthly aggregations, got %d", len(result))
@@ -209,7 +255,7 @@ func TestAggregateBySession(t *testing.T) {
 		},
 	}
 
-	result := AggregateBySession(messages)
+	result := Calculator{}.AggregateBySession(messages)
 
 	if len(result) != 2 {
 		t.Fatalf("Expected 2 session aggregations, got %d", len(result))
@@ -256,14 +302,14 @@ func TestAggregateByModel(t *testing.T) {
 		},
 	}
 
-	result := AggregateByModel(messages)
+	result := Calculator{}.AggregateByModel(messages)
 
 	if len(result) != 2 {
 		t.Fatalf("Expected 2 model aggregations, got %d", len(result))
 	}
 
 	// Should be sorted by cost (descending)
-	if result[0].CostUSD < result[1].CostUSD {
+	if result[0].Cost < result[1].Cost {
 		t.Error("Results are not sorted by cost in descending order")
 	}
 
@@ -285,3 +331,615 @@ func TestAggregateByModel(t *testing.T) {
 		t.Errorf("Opus output tokens = %d, want 3500", opus.TokenUsage.OutputTokens)
 	}
 }
+
+func TestAggregateByProject(t *testing.T) {
+	messages := []models.Message{
+		{
+			SessionID:  "session1",
+			Project:    "/home/me/src/web",
+			Timestamp:  time.Now(),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 1000, OutputTokens: 2000},
+		},
+		{
+			SessionID:  "session2",
+			Project:    "/home/me/src/api",
+			Timestamp:  time.Now(),
+			Model:      "claude-sonnet-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 500, OutputTokens: 1500},
+		},
+		{
+			SessionID:  "session3",
+			Project:    "/home/me/src/web",
+			Timestamp:  time.Now(),
+			Model:      "claude-sonnet-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 2000, OutputTokens: 3000},
+		},
+	}
+
+	result := Calculator{}.AggregateByProject(messages)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 project aggregations, got %d", len(result))
+	}
+
+	// Should be sorted by project path
+	if result[0].Project != "/home/me/src/api" || result[1].Project != "/home/me/src/web" {
+		t.Errorf("Projects = [%s %s], want [/home/me/src/api /home/me/src/web]", result[0].Project, result[1].Project)
+	}
+
+	web := result[1]
+	if web.TokenUsage.InputTokens != 3000 {
+		t.Errorf("Web input tokens = %d, want 3000", web.TokenUsage.InputTokens)
+	}
+	if len(web.Models) != 2 {
+		t.Errorf("Web models count = %d, want 2", len(web.Models))
+	}
+	expectedCost := CalculateCost(messages[0].TokenUsage, messages[0].Model) + CalculateCost(messages[2].TokenUsage, messages[2].Model)
+	if web.Cost != expectedCost {
+		t.Errorf("Web cost = %v, want %v", web.Cost, expectedCost)
+	}
+}
+
+func TestAggregateBlocks(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 20, 0, 0, time.UTC)
+	messages := []models.Message{
+		{
+			Timestamp:  baseTime.Add(1 * time.Hour),
+			Model:      "claude-sonnet-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 500, OutputTokens: 1500},
+		},
+		{
+			Timestamp:  baseTime,
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 1000, OutputTokens: 2000},
+		},
+		{
+			// Past the end of the first block (15:00), so a new block starts at 16:00
+			Timestamp:  baseTime.Add(6 * time.Hour),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 2000, OutputTokens: 3000},
+		},
+	}
+
+	now := time.Date(2025, 1, 15, 17, 0, 0, 0, time.UTC)
+	result := Calculator{}.AggregateBlocks(messages, now)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 blocks, got %d", len(result))
+	}
+
+	first := result[0]
+	if !first.StartTime.Equal(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("First block start = %v, want 10:00", first.StartTime)
+	}
+	if !first.EndTime.Equal(first.StartTime.Add(BlockDuration)) {
+		t.Errorf("First block end = %v, want start + 5h", first.EndTime)
+	}
+	if first.TokenUsage.InputTokens != 1500 {
+		t.Errorf("First block input tokens = %d, want 1500", first.TokenUsage.InputTokens)
+	}
+	if len(first.Models) != 2 {
+		t.Errorf("First block models count = %d, want 2", len(first.Models))
+	}
+	if first.IsActive || first.Projection != nil {
+		t.Error("First block should not be active")
+	}
+
+	second := result[1]
+	if !second.StartTime.Equal(time.Date(2025, 1, 15, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("Second block start = %v, want 16:00", second.StartTime)
+	}
+	if !second.IsActive || second.Projection == nil {
+		t.Fatal("Second block should be active with a projection")
+	}
+
+	p := second.Projection
+	if p.Elapsed != time.Hour || p.Remaining != 4*time.Hour {
+		t.Errorf("Elapsed/remaining = %v/%v, want 1h/4h", p.Elapsed, p.Remaining)
+	}
+	// 5000 tokens in the first hour projects to 25000 over the whole block
+	if p.ProjectedTokens != 25000 {
+		t.Errorf("Projected tokens = %d, want 25000", p.ProjectedTokens)
+	}
+	expectedCost := second.Cost * 5
+	if diff := p.ProjectedCost - expectedCost; diff.Abs() > 1 {
+		t.Errorf("Projected cost = %v, want %v", p.ProjectedCost, expectedCost)
+	}
+}
+
+func TestAggregateBlocks_NoActiveBlock(t *testing.T) {
+	messages := []models.Message{
+		{
+			Timestamp:  time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 1000},
+		},
+	}
+
+	result := Calculator{}.AggregateBlocks(messages, time.Date(2025, 1, 16, 10, 0, 0, 0, time.UTC))
+
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 block, got %d", len(result))
+	}
+	if result[0].IsActive {
+		t.Error("Block should not be active a day later")
+	}
+}
+
+func TestAggregateDaily_TimeZone(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	messages := []models.Message{
+		{
+			// Jan 15 in UTC, but already Jan 16 in Tokyo
+			Timestamp:  time.Date(2025, 1, 15, 20, 0, 0, 0, time.UTC).In(tokyo),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 1000},
+		},
+		{
+			Timestamp:  time.Date(2025, 1, 16, 1, 0, 0, 0, time.UTC).In(tokyo),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 500},
+		},
+	}
+
+	result := Calculator{}.AggregateDaily(messages)
+
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 daily aggregation, got %d", len(result))
+	}
+
+	expected := time.Date(2025, 1, 16, 0, 0, 0, 0, tokyo)
+	if !result[0].Date.Equal(expected) {
+		t.Errorf("Date = %v, want %v", result[0].Date, expected)
+	}
+	if result[0].Date.Format("2006-01-02") != "2025-01-16" {
+		t.Errorf("Formatted date = %s, want 2025-01-16", result[0].Date.Format("2006-01-02"))
+	}
+}
+
+func TestAggregateBlocks_TimeZone(t *testing.T) {
+	// Half-hour offsets must not shift block starts off the local hour
+	india := time.FixedZone("IST", 5*60*60+30*60)
+	messages := []models.Message{
+		{
+			Timestamp:  time.Date(2025, 1, 15, 10, 45, 0, 0, india),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 1000},
+		},
+	}
+
+	result := Calculator{}.AggregateBlocks(messages, time.Date(2025, 1, 16, 0, 0, 0, 0, india))
+
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 block, got %d", len(result))
+	}
+	expected := time.Date(2025, 1, 15, 10, 0, 0, 0, india)
+	if !result[0].StartTime.Equal(expected) {
+		t.Errorf("Block start = %v, want %v", result[0].StartTime, expected)
+	}
+}
+
+func TestAggregateWeekly(t *testing.T) {
+	messages := []models.Message{
+		{
+			// Sunday, Jan 5 2025
+			Timestamp:  time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 1000},
+		},
+		{
+			// Monday, Jan 6 2025
+			Timestamp:  time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC),
+			Model:      "claude-sonnet-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 500},
+		},
+		{
+			// Saturday, Jan 11 2025
+			Timestamp:  time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 2000},
+		},
+	}
+
+	type week struct {
+		year, week int
+		start      time.Time
+		input      int
+	}
+
+	tests := []struct {
+		name      string
+		weekStart time.Weekday
+		expected  []week
+	}{
+		{
+			name:      "ISO weeks starting Monday",
+			weekStart: time.Monday,
+			expected: []week{
+				{2025, 1, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), 1000},
+				{2025, 2, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 2500},
+			},
+		},
+		{
+			name:      "Weeks starting Sunday",
+			weekStart: time.Sunday,
+			expected: []week{
+				{2025, 2, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), 3500},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Calculator{}.AggregateWeekly(messages, tt.weekStart)
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d weekly aggregations, got %d", len(tt.expected), len(result))
+			}
+
+			for i, want := range tt.expected {
+				got := result[i]
+				if got.Year != want.year || got.Week != want.week {
+					t.Errorf("Week %d = %d-W%02d, want %d-W%02d", i, got.Year, got.Week, want.year, want.week)
+				}
+				if !got.StartDate.Equal(want.start) {
+					t.Errorf("Week %d start = %v, want %v", i, got.StartDate, want.start)
+				}
+				if got.TokenUsage.InputTokens != want.input {
+					t.Errorf("Week %d input tokens = %d, want %d", i, got.TokenUsage.InputTokens, want.input)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateCostAt(t *testing.T) {
+	usage := models.TokenUsage{InputTokens: 1000000, OutputTokens: 1000000}
+	model := "claude-3-5-haiku-20241022"
+
+	launch := CalculateCostAt(usage, model, time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC))
+	if launch != models.USD(1.0+5.0) {
+		t.Errorf("Cost at launch price = %v, want 6", launch)
+	}
+
+	current := CalculateCostAt(usage, model, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+	if current != CalculateCost(usage, model) {
+		t.Errorf("Cost at current price = %v, want %v", current, CalculateCost(usage, model))
+	}
+}
+
+func TestAggregateByModel_EqualCosts(t *testing.T) {
+	cost := models.USD(1)
+	var messages []models.Message
+	for _, m := range []struct{ model, tier string }{
+		{"claude-sonnet-4-20250514", models.ServiceTierStandard},
+		{"claude-opus-4-20250514", models.ServiceTierStandard},
+		{"claude-opus-4-20250514", models.ServiceTierBatch},
+	} {
+		messages = append(messages, models.Message{
+			Timestamp:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			Model:           m.model,
+			ServiceTier:     m.tier,
+			RecordedCostUSD: &cost,
+		})
+	}
+	// Long context rows tie with their model's other rows too
+	messages = append(messages, models.Message{
+		Timestamp:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		Model:           "claude-sonnet-4-20250514",
+		ServiceTier:     models.ServiceTierStandard,
+		TokenUsage:      models.TokenUsage{InputTokens: 300_000},
+		RecordedCostUSD: &cost,
+	})
+
+	expected := []string{
+		"claude-opus-4-20250514 batch false",
+		"claude-opus-4-20250514 standard false",
+		"claude-sonnet-4-20250514 standard false",
+		"claude-sonnet-4-20250514 standard true",
+	}
+	// Rows come from a map, so ties would show up in varying order
+	for run := 0; run < 20; run++ {
+		result := Calculator{CostMode: models.CostModeDisplay}.AggregateByModel(messages)
+		if len(result) != len(expected) {
+			t.Fatalf("Expected %d breakdowns, got %d", len(expected), len(result))
+		}
+		for i, b := range result {
+			if got := fmt.Sprintf("%s %s %t", b.Model, b.ServiceTier, b.LongContext); got != expected[i] {
+				t.Fatalf("Row %d = %s, want %s", i, got, expected[i])
+			}
+		}
+	}
+}
+
+func TestAggregateByModel_PricePeriods(t *testing.T) {
+	messages := []models.Message{
+		{
+			Timestamp:  time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC),
+			Model:      "claude-3-5-haiku-20241022",
+			TokenUsage: models.TokenUsage{InputTokens: 1000},
+		},
+		{
+			Timestamp:  time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+			Model:      "claude-3-5-haiku-20241022",
+			TokenUsage: models.TokenUsage{InputTokens: 1000},
+		},
+	}
+
+	result := Calculator{}.AggregateByModel(messages)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected one breakdown per price period, got %d", len(result))
+	}
+	for _, b := range result {
+		if b.PricePeriod == "" {
+			t.Errorf("Breakdown for %s has no price period", b.Model)
+		}
+	}
+}
+
+func TestCalculateCost_ResolvedModels(t *testing.T) {
+	usage := models.TokenUsage{InputTokens: 1000000}
+
+	tests := []struct {
+		model    string
+		expected models.Money
+	}{
+		{"anthropic.claude-3-5-sonnet-20241022-v2:0", models.USD(3.0)},
+		// Resolved to Sonnet 4, long context rates included
+		{"claude-sonnet-4-20260101", models.USD(6.0)},
+		{models.SyntheticModel, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			result := CalculateCost(usage, tt.model)
+			if result != tt.expected {
+				t.Errorf("CalculateCost() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindUnpricedModels(t *testing.T) {
+	messages := []models.Message{
+		{Model: "claude-opus-4-20250514", TokenUsage: models.TokenUsage{InputTokens: 1000}},
+		{Model: "gpt-4o", TokenUsage: models.TokenUsage{InputTokens: 100, OutputTokens: 50}},
+		{Model: "gpt-4o", TokenUsage: models.TokenUsage{InputTokens: 100}},
+		{Model: "mystery-model", TokenUsage: models.TokenUsage{OutputTokens: 10}},
+		{Model: "claude-sonnet-4-20260101", TokenUsage: models.TokenUsage{OutputTokens: 20}},
+		{Model: "claude-opus-4-5", TokenUsage: models.TokenUsage{OutputTokens: 5}},
+		{Model: models.SyntheticModel},
+	}
+
+	result := Calculator{}.FindUnpricedModels(messages)
+
+	if len(result) != 4 {
+		t.Fatalf("Expected 4 unpriced models, got %d: %v", len(result), result)
+	}
+	if result[0].Model != "gpt-4o" || result[0].Messages != 2 || result[0].TokenUsage.Total() != 250 {
+		t.Errorf("First unpriced model = %+v, want gpt-4o with 2 messages and 250 tokens", result[0])
+	}
+	if result[1].Model != "claude-sonnet-4-20260101" || result[1].PricedAs != "claude-sonnet-4-20250514" || result[1].Pricing.InputPer1M != 3 {
+		t.Errorf("Second unpriced model = %+v, want claude-sonnet-4-20260101 priced as claude-sonnet-4-20250514", result[1])
+	}
+	if result[2].Model != "mystery-model" {
+		t.Errorf("Third unpriced model = %s, want mystery-model", result[2].Model)
+	}
+	// A new minor version is not priced as its major version
+	if result[3].Model != "claude-opus-4-5" || result[3].PricedAs != "" {
+		t.Errorf("Fourth unpriced model = %+v, want claude-opus-4-5 without a price", result[3])
+	}
+}
+
+func TestCalculateCost_LongContext(t *testing.T) {
+	model := "claude-sonnet-4-20250514"
+	at := time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC)
+	long := models.TokenUsage{InputTokens: 250_000, OutputTokens: 10_000}
+	short := models.TokenUsage{InputTokens: 150_000, OutputTokens: 10_000}
+
+	// 250K * $6 + 10K * $22.50
+	if cost := CalculateCostAt(long, model, at); cost != models.USD(1.725) {
+		t.Errorf("Long context cost = %v, want 1.725", cost)
+	}
+
+	// Tiers apply per request: two short requests stay at the base rate even
+	// though together they exceed the threshold
+	messages := []models.Message{
+		{SessionID: "s1", Timestamp: at, Model: model, TokenUsage: short},
+		{SessionID: "s1", Timestamp: at.Add(time.Minute), Model: model, TokenUsage: short},
+		{SessionID: "s1", Timestamp: at.Add(2 * time.Minute), Model: model, TokenUsage: long},
+	}
+	expected := models.USD(2*(0.45+0.15) + 1.725)
+
+	sessions := Calculator{}.AggregateBySession(messages)
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %d", len(sessions))
+	}
+	if sessions[0].Cost != expected {
+		t.Errorf("Session cost = %v, want %v", sessions[0].Cost, expected)
+	}
+
+	breakdown := Calculator{}.AggregateByModel(messages)
+	if len(breakdown) != 2 {
+		t.Fatalf("Expected long context requests in their own breakdown, got %d", len(breakdown))
+	}
+	for _, b := range breakdown {
+		if b.LongContext && b.TokenUsage.InputTokens != 250_000 {
+			t.Errorf("Long context breakdown input = %d, want 250000", b.TokenUsage.InputTokens)
+		}
+	}
+}
+
+func TestMessageCost(t *testing.T) {
+	recorded := models.USD(0.5)
+	withCost := models.Message{
+		Timestamp:       time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		Model:           "claude-sonnet-4-20250514",
+		TokenUsage:      models.TokenUsage{InputTokens: 125000},
+		RecordedCostUSD: &recorded,
+	}
+	withoutCost := withCost
+	withoutCost.RecordedCostUSD = nil
+
+	tests := []struct {
+		mode        models.CostMode
+		withCost    models.Money
+		withoutCost models.Money
+	}{
+		{models.CostModeAuto, models.USD(0.5), models.USD(0.375)},
+		{models.CostModeCalculate, models.USD(0.375), models.USD(0.375)},
+		{models.CostModeDisplay, models.USD(0.5), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			calc := Calculator{CostMode: tt.mode}
+
+			if cost := calc.MessageCost(withCost); cost != tt.withCost {
+				t.Errorf("MessageCost() with recorded cost = %v, want %v", cost, tt.withCost)
+			}
+			if cost := calc.MessageCost(withoutCost); cost != tt.withoutCost {
+				t.Errorf("MessageCost() without recorded cost = %v, want %v", cost, tt.withoutCost)
+			}
+
+			daily := calc.AggregateDaily([]models.Message{withCost, withoutCost})
+			if len(daily) != 1 || daily[0].Cost != tt.withCost+tt.withoutCost {
+				t.Errorf("AggregateDaily() cost = %v, want %v", daily, tt.withCost+tt.withoutCost)
+			}
+		})
+	}
+}
+
+func TestFindUnpricedModels_RecordedCost(t *testing.T) {
+	recorded := models.USD(0.1)
+	messages := []models.Message{
+		{Model: "mystery-model", TokenUsage: models.TokenUsage{InputTokens: 100}, RecordedCostUSD: &recorded},
+	}
+
+	if unpriced := (Calculator{}).FindUnpricedModels(messages); len(unpriced) != 0 {
+		t.Errorf("Auto mode should not report messages with a recorded cost, got %v", unpriced)
+	}
+
+	calc := Calculator{CostMode: models.CostModeCalculate}
+	if unpriced := calc.FindUnpricedModels(messages); len(unpriced) != 1 {
+		t.Errorf("Calculate mode should report the unpriced model, got %v", unpriced)
+	}
+}
+
+func TestAuditCosts(t *testing.T) {
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	recordedSonnet := models.USD(0.5)
+	recordedOpus := models.USD(1.875)
+	messages := []models.Message{
+		{Timestamp: at, Model: "claude-sonnet-4-20250514", TokenUsage: models.TokenUsage{InputTokens: 125000}, RecordedCostUSD: &recordedSonnet},
+		{Timestamp: at, Model: "claude-sonnet-4-20250514", TokenUsage: models.TokenUsage{InputTokens: 125000}},
+		{Timestamp: at, Model: "claude-opus-4-20250514", TokenUsage: models.TokenUsage{InputTokens: 125000}, RecordedCostUSD: &recordedOpus},
+	}
+
+	result := Calculator{}.AuditCosts(messages)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 audited models, got %d", len(result))
+	}
+
+	// Sorted by absolute difference: Sonnet differs by $0.125, Opus not at all
+	sonnet := result[0]
+	if sonnet.Model != "claude-sonnet-4-20250514" || sonnet.Messages != 1 {
+		t.Errorf("First audit = %+v, want Sonnet 4 with 1 message", sonnet)
+	}
+	if sonnet.RecordedCost != models.USD(0.5) || sonnet.CalculatedCost != models.USD(0.375) || sonnet.Difference != models.USD(-0.125) {
+		t.Errorf("Sonnet 4 audit = %+v", sonnet)
+	}
+	if result[1].Difference != 0 {
+		t.Errorf("Opus 4 difference = %v, want 0", result[1].Difference)
+	}
+}
+
+func TestAggregateDaily_OrderIndependent(t *testing.T) {
+	base := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	var messages []models.Message
+	for i := 0; i < 1000; i++ {
+		messages = append(messages, models.Message{
+			Timestamp:  base.Add(time.Duration(i) * time.Second),
+			Model:      "claude-3-haiku-20240307",
+			TokenUsage: models.TokenUsage{InputTokens: 7 + i, OutputTokens: 13, CacheReadTokens: 101},
+		})
+	}
+
+	reversed := make([]models.Message, len(messages))
+	for i, msg := range messages {
+		reversed[len(messages)-1-i] = msg
+	}
+
+	forward := Calculator{}.AggregateDaily(messages)
+	backward := Calculator{}.AggregateDaily(reversed)
+	if forward[0].Cost != backward[0].Cost {
+		t.Errorf("Cost depends on order: %v vs %v", forward[0].Cost, backward[0].Cost)
+	}
+}
+
+func TestAggregateMonthly_Currency(t *testing.T) {
+	calc := Calculator{ExchangeRate: &models.ExchangeRate{
+		Rate:    150,
+		Monthly: map[string]float64{"2025-05": 100},
+	}}
+
+	usage := models.TokenUsage{InputTokens: 125000}
+	messages := []models.Message{
+		{Timestamp: time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC), Model: "claude-sonnet-4-20250514", TokenUsage: usage},
+		{Timestamp: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), Model: "claude-sonnet-4-20250514", TokenUsage: usage},
+	}
+
+	result := calc.AggregateMonthly(messages)
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 months, got %d", len(result))
+	}
+
+	// $0.375 each, converted at the rate of its own month
+	if result[0].Cost != models.USD(37.5) || result[1].Cost != models.USD(56.25) {
+		t.Errorf("Monthly costs = %v, %v, want 37.5, 56.25", result[0].Cost, result[1].Cost)
+	}
+
+	breakdown := calc.AggregateByModel(messages)
+	if len(breakdown) != 1 || breakdown[0].Cost != models.USD(93.75) {
+		t.Errorf("Breakdown = %+v, want a total of 93.75", breakdown)
+	}
+}
+
+func TestAggregateByServiceTier(t *testing.T) {
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	usage := models.TokenUsage{InputTokens: 125000}
+	messages := []models.Message{
+		{Timestamp: at, Model: "claude-sonnet-4-20250514", ServiceTier: models.ServiceTierBatch, TokenUsage: usage},
+		{Timestamp: at, Model: "claude-sonnet-4-20250514", ServiceTier: models.ServiceTierPriority, TokenUsage: usage},
+		{Timestamp: at, Model: "claude-sonnet-4-20250514", TokenUsage: usage},
+		{Timestamp: at, Model: "claude-opus-4-20250514", ServiceTier: models.ServiceTierStandard, TokenUsage: usage},
+	}
+
+	result := Calculator{}.AggregateByServiceTier(messages)
+
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 tiers, got %d", len(result))
+	}
+
+	expected := []struct {
+		tier string
+		cost models.Money
+	}{
+		{models.ServiceTierStandard, models.USD(0.375 + 1.875)},
+		{models.ServiceTierBatch, models.USD(0.1875)},
+		{models.ServiceTierPriority, models.USD(0.375)},
+	}
+	for i, want := range expected {
+		if result[i].ServiceTier != want.tier || result[i].Cost != want.cost {
+			t.Errorf("Tier %d = %s at %v, want %s at %v", i, result[i].ServiceTier, result[i].Cost, want.tier, want.cost)
+		}
+	}
+
+	breakdown := Calculator{}.AggregateByModel(messages)
+	if len(breakdown) != 4 {
+		t.Errorf("Expected each tier in its own breakdown, got %d", len(breakdown))
+	}
+}
@@ -0,0 +1,124 @@
+package calculator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// ComparePlans compares the API-equivalent cost of messages with plan for
+// every month from the first with usage to the last, and finds the cheapest
+// option among paying API prices and all subscription plans. Months without
+// usage cost nothing at API prices but the full price on a plan. The
+// subscription plan named like plan is taken at plan's price, so that an
+// overridden price counts in both.
+//
+// proBlockLimitUSD is the API-equivalent cost, in US dollars, that a Pro plan
+// allows per 5-hour block; other plans allow their UsageMultiplier times as
+// much. Like plan prices, it is converted at each month's rate. A plan whose
+// allowance is below a month's busiest block is not an option for that
+// month. Zero means limits are unknown and ignored.
+func (c Calculator) ComparePlans(messages []models.Message, plan models.SubscriptionPlan, proBlockLimitUSD models.Money) models.PlanComparison {
+	comparison := models.PlanComparison{
+		Plan:      plan,
+		PlanPrice: c.convert(plan.PriceUSD, time.Now()),
+	}
+
+	monthly := c.AggregateMonthly(messages)
+	if len(monthly) == 0 {
+		return comparison
+	}
+	costs := make(map[string]models.Money)
+	for _, m := range monthly {
+		costs[fmt.Sprintf("%d-%02d", m.Year, m.Month)] = m.Cost
+	}
+
+	daily := c.AggregateDaily(messages)
+	peaks := make(map[string]models.Money)
+	for _, block := range c.AggregateBlocks(messages, time.Time{}) {
+		key := block.StartTime.Format("2006-01")
+		if block.Cost > peaks[key] {
+			peaks[key] = block.Cost
+		}
+	}
+
+	optionTotals := make(map[string]models.Money)
+	viable := map[string]bool{models.PayAsYouGo: true}
+	for _, p := range models.SubscriptionPlans {
+		viable[p.Name] = true
+	}
+
+	first, last := monthly[0], monthly[len(monthly)-1]
+	lastStart := time.Date(last.Year, last.Month, 1, 0, 0, 0, 0, c.location())
+	for monthStart := time.Date(first.Year, first.Month, 1, 0, 0, 0, 0, c.location()); !monthStart.After(lastStart); monthStart = monthStart.AddDate(0, 1, 0) {
+		key := monthStart.Format("2006-01")
+		limit := c.convert(proBlockLimitUSD, monthStart)
+
+		month := models.PlanMonth{
+			Year:          monthStart.Year(),
+			Month:         monthStart.Month(),
+			APICost:       costs[key],
+			PlanPrice:     c.convert(plan.PriceUSD, monthStart),
+			PeakBlockCost: peaks[key],
+		}
+		month.Savings = month.APICost - month.PlanPrice
+		if month.PlanPrice > 0 {
+			month.ValueMultiplier = month.APICost.Float64() / month.PlanPrice.Float64()
+		}
+
+		var cumulative models.Money
+		for _, d := range daily {
+			if d.Date.Format("2006-01") != key {
+				continue
+			}
+			cumulative += d.Cost
+			if cumulative >= month.PlanPrice {
+				breakEven := d.Date
+				month.BreakEven = &breakEven
+				break
+			}
+		}
+
+		month.CheapestPlan = models.PayAsYouGo
+		month.CheapestCost = month.APICost
+		optionTotals[models.PayAsYouGo] += month.APICost
+		for _, p := range models.SubscriptionPlans {
+			if p.Name == plan.Name {
+				p = plan
+			}
+			price := c.convert(p.PriceUSD, monthStart)
+			optionTotals[p.Name] += price
+
+			if limit > 0 && month.PeakBlockCost > limit*models.Money(p.UsageMultiplier) {
+				viable[p.Name] = false
+				continue
+			}
+			if price < month.CheapestCost {
+				month.CheapestPlan = p.Name
+				month.CheapestCost = price
+			}
+		}
+
+		comparison.Months = append(comparison.Months, month)
+		comparison.PlanPrice = month.PlanPrice
+		comparison.APICost += month.APICost
+		comparison.PlanCost += month.PlanPrice
+	}
+
+	comparison.Savings = comparison.APICost - comparison.PlanCost
+	if comparison.PlanCost > 0 {
+		comparison.ValueMultiplier = comparison.APICost.Float64() / comparison.PlanCost.Float64()
+	}
+
+	comparison.CheapestPlan = models.PayAsYouGo
+	comparison.CheapestCost = optionTotals[models.PayAsYouGo]
+	for _, p := range models.SubscriptionPlans {
+		if viable[p.Name] && optionTotals[p.Name] < comparison.CheapestCost {
+			comparison.CheapestPlan = p.Name
+			comparison.CheapestCost = optionTotals[p.Name]
+		}
+	}
+
+	return comparison
+}
@@ -0,0 +1,183 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+func TestComparePlans(t *testing.T) {
+	opus := "claude-opus-4-20250514"
+	// $15 of Opus 4 input per message
+	usage := models.TokenUsage{InputTokens: 1_000_000}
+
+	var messages []models.Message
+	// January: $15 on each of the first 10 days, $150 in total
+	for day := 1; day <= 10; day++ {
+		messages = append(messages, models.Message{
+			Timestamp:  time.Date(2025, 1, day, 10, 0, 0, 0, time.UTC),
+			Model:      opus,
+			TokenUsage: usage,
+		})
+	}
+	// February: a single $15 message
+	messages = append(messages, models.Message{
+		Timestamp:  time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC),
+		Model:      opus,
+		TokenUsage: usage,
+	})
+
+	plan, _ := models.FindSubscriptionPlan("max5x")
+	result := Calculator{}.ComparePlans(messages, plan, 0)
+
+	if len(result.Months) != 2 {
+		t.Fatalf("Expected 2 months, got %d", len(result.Months))
+	}
+
+	jan := result.Months[0]
+	if jan.APICost != models.USD(150) || jan.PlanPrice != models.USD(100) {
+		t.Errorf("January API cost/plan price = %v/%v, want 150/100", jan.APICost, jan.PlanPrice)
+	}
+	if jan.ValueMultiplier != 1.5 || jan.Savings != models.USD(50) {
+		t.Errorf("January value = %v, savings = %v, want 1.5 and 50", jan.ValueMultiplier, jan.Savings)
+	}
+	// $105 is reached on the 7th
+	if jan.BreakEven == nil || jan.BreakEven.Day() != 7 {
+		t.Errorf("January break-even = %v, want 2025-01-07", jan.BreakEven)
+	}
+	if jan.CheapestPlan != "Pro" {
+		t.Errorf("January cheapest plan = %s, want Pro without limits", jan.CheapestPlan)
+	}
+
+	feb := result.Months[1]
+	if feb.BreakEven != nil {
+		t.Errorf("February should not break even, got %v", feb.BreakEven)
+	}
+	if feb.CheapestPlan != models.PayAsYouGo || feb.CheapestCost != models.USD(15) {
+		t.Errorf("February cheapest = %s at %v, want API at 15", feb.CheapestPlan, feb.CheapestCost)
+	}
+
+	if result.APICost != models.USD(165) || result.PlanCost != models.USD(200) {
+		t.Errorf("Totals = %v/%v, want 165/200", result.APICost, result.PlanCost)
+	}
+	if result.CheapestPlan != "Pro" || result.CheapestCost != models.USD(40) {
+		t.Errorf("Cheapest overall = %s at %v, want Pro at 40", result.CheapestPlan, result.CheapestCost)
+	}
+}
+
+func TestComparePlans_BlockLimit(t *testing.T) {
+	opus := "claude-opus-4-20250514"
+	// A $45 block and five $15 days, $120 in total
+	messages := []models.Message{
+		{Timestamp: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), Model: opus, TokenUsage: models.TokenUsage{InputTokens: 3_000_000}},
+	}
+	for day := 2; day <= 6; day++ {
+		messages = append(messages, models.Message{
+			Timestamp:  time.Date(2025, 1, day, 10, 0, 0, 0, time.UTC),
+			Model:      opus,
+			TokenUsage: models.TokenUsage{InputTokens: 1_000_000},
+		})
+	}
+
+	plan, _ := models.FindSubscriptionPlan("pro")
+	// Pro allows $10 per block, so Max 5x ($50) is the smallest plan that fits
+	result := Calculator{}.ComparePlans(messages, plan, models.USD(10))
+
+	if result.Months[0].PeakBlockCost != models.USD(45) {
+		t.Errorf("Peak block = %v, want 45", result.Months[0].PeakBlockCost)
+	}
+	if result.Months[0].CheapestPlan != "Max 5x" {
+		t.Errorf("Cheapest plan = %s, want Max 5x", result.Months[0].CheapestPlan)
+	}
+	if result.CheapestPlan != "Max 5x" {
+		t.Errorf("Cheapest overall = %s, want Max 5x", result.CheapestPlan)
+	}
+
+	// The limit is in USD and converted like plan prices: $10 is 1,000 at 100
+	// to the dollar, so Max 5x allows 5,000 against a 4,500 block
+	calc := Calculator{ExchangeRate: &models.ExchangeRate{Rate: 100}}
+	result = calc.ComparePlans(messages, plan, models.USD(10))
+	if result.Months[0].PeakBlockCost != models.USD(4500) || result.Months[0].CheapestPlan != "Max 5x" {
+		t.Errorf("Converted peak block = %v, cheapest = %s, want 4500 and Max 5x",
+			result.Months[0].PeakBlockCost, result.Months[0].CheapestPlan)
+	}
+}
+
+func TestComparePlans_GapMonth(t *testing.T) {
+	opus := "claude-opus-4-20250514"
+	// $30 in January, nothing in February and $15 in March
+	messages := []models.Message{
+		{Timestamp: time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), Model: opus, TokenUsage: models.TokenUsage{InputTokens: 1_000_000}},
+		{Timestamp: time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC), Model: opus, TokenUsage: models.TokenUsage{InputTokens: 1_000_000}},
+		{Timestamp: time.Date(2025, 3, 5, 10, 0, 0, 0, time.UTC), Model: opus, TokenUsage: models.TokenUsage{InputTokens: 1_000_000}},
+	}
+
+	plan, _ := models.FindSubscriptionPlan("pro")
+	result := Calculator{}.ComparePlans(messages, plan, 0)
+
+	if len(result.Months) != 3 {
+		t.Fatalf("Expected 3 months including February, got %d", len(result.Months))
+	}
+	feb := result.Months[1]
+	if feb.Month != time.February || feb.APICost != 0 || feb.PlanPrice != models.USD(20) || feb.Savings != models.USD(-20) {
+		t.Errorf("February = %s: API %v, plan %v, savings %v, want 0, 20 and -20", feb.Month, feb.APICost, feb.PlanPrice, feb.Savings)
+	}
+	if result.APICost != models.USD(45) || result.PlanCost != models.USD(60) {
+		t.Errorf("Totals = %v/%v, want 45/60", result.APICost, result.PlanCost)
+	}
+	// Pro over three months costs $60, more than the $45 of API usage
+	if result.CheapestPlan != models.PayAsYouGo || result.CheapestCost != models.USD(45) {
+		t.Errorf("Cheapest overall = %s at %v, want API at 45", result.CheapestPlan, result.CheapestCost)
+	}
+}
+
+func TestComparePlans_Location(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	// 2025-02-01 03:00 in Tokyo is still January in UTC
+	messages := []models.Message{
+		{Timestamp: time.Date(2025, 2, 1, 3, 0, 0, 0, tokyo), Model: "claude-opus-4-20250514", TokenUsage: models.TokenUsage{InputTokens: 1_000_000}},
+	}
+
+	plan, _ := models.FindSubscriptionPlan("pro")
+	calc := Calculator{
+		ExchangeRate: &models.ExchangeRate{Rate: 100, Monthly: map[string]float64{"2025-01": 100, "2025-02": 200}},
+		Location:     tokyo,
+	}
+	result := calc.ComparePlans(messages, plan, 0)
+
+	if len(result.Months) != 1 || result.Months[0].Month != time.February {
+		t.Fatalf("Months = %+v, want February only", result.Months)
+	}
+	if result.Months[0].PlanPrice != models.USD(4000) || result.Months[0].APICost != models.USD(3000) {
+		t.Errorf("February plan price/API cost = %v/%v, want 4000/3000 at February's rate", result.Months[0].PlanPrice, result.Months[0].APICost)
+	}
+}
+
+func TestComparePlans_PlanPrice(t *testing.T) {
+	// $15 of Opus 4 input on each of the first 10 days of January, $150 in total
+	var messages []models.Message
+	for day := 1; day <= 10; day++ {
+		messages = append(messages, models.Message{
+			Timestamp:  time.Date(2025, 1, day, 10, 0, 0, 0, time.UTC),
+			Model:      "claude-opus-4-20250514",
+			TokenUsage: models.TokenUsage{InputTokens: 1_000_000},
+		})
+	}
+
+	// At $200 Pro is no longer the cheapest option; Max 5x at $100 is
+	plan, _ := models.FindSubscriptionPlan("pro")
+	plan.PriceUSD = models.USD(200)
+	calc := Calculator{ExchangeRate: &models.ExchangeRate{Rate: 150, Monthly: map[string]float64{"2025-01": 100}}}
+	result := calc.ComparePlans(messages, plan, 0)
+
+	if result.PlanPrice != models.USD(20000) {
+		t.Errorf("Plan price = %v, want 20000 at January's rate", result.PlanPrice)
+	}
+	if result.Months[0].CheapestPlan != "Max 5x" || result.Months[0].CheapestCost != models.USD(10000) {
+		t.Errorf("January cheapest = %s at %v, want Max 5x at 10000", result.Months[0].CheapestPlan, result.Months[0].CheapestCost)
+	}
+	if result.CheapestPlan != "Max 5x" {
+		t.Errorf("Cheapest overall = %s, want Max 5x", result.CheapestPlan)
+	}
+}
@@ -0,0 +1,98 @@
+package display
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// CSVRenderer writes a report as CSV, or TSV with a tab Comma. Cells are
+// written as their raw values: numbers in full, zero included, and costs
+// exactly, so that spreadsheets can sum them. A report with breakdown
+// sections is written as one row per section and model instead of one row per
+// period, with the model's price period, service tier and long context in
+// columns of their own. Totals appends the totals row, and Costs names the
+// cost columns.
+type CSVRenderer struct {
+	Comma  rune
+	Totals bool
+	Costs  CostFormat
+}
+
+func (r CSVRenderer) Render(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+	if r.Comma != 0 {
+		writer.Comma = r.Comma
+	}
+
+	columns := report.Columns
+	if len(report.Sections) > 0 {
+		keys := len(report.Sections[0].Keys)
+		columns = append(append([]Column{}, report.Columns[:keys]...), report.Sections[0].Columns...)
+	}
+	if err := writer.Write(r.header(columns)); err != nil {
+		return err
+	}
+
+	if len(report.Sections) > 0 {
+		for _, section := range report.Sections {
+			for _, row := range section.Rows {
+				if err := writer.Write(cellValues(columns, append(append(Row{}, section.Keys...), row...))); err != nil {
+					return err
+				}
+			}
+		}
+	} else {
+		for _, row := range report.Rows {
+			if err := writer.Write(cellValues(columns, row)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.Totals && report.Totals != nil {
+		if err := writer.Write(cellValues(columns, report.Totals)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// header names a column with Fields by its fields.
+func (r CSVRenderer) header(columns []Column) []string {
+	var names []string
+	for _, c := range columns {
+		if len(c.Fields) > 0 {
+			names = append(names, c.Fields...)
+			continue
+		}
+		names = append(names, r.Costs.columnName(c))
+	}
+	return names
+}
+
+// cellValues writes the Fields of the cells of columns with Fields, blank
+// where a cell has none, and the Value of other cells.
+func cellValues(columns []Column, row Row) []string {
+	var values []string
+	for i, cell := range row {
+		if i < len(columns) && len(columns[i].Fields) > 0 {
+			for k := range columns[i].Fields {
+				var value string
+				if k < len(cell.Fields) && cell.Fields[k] != nil {
+					value = fmt.Sprint(cell.Fields[k])
+				}
+				values = append(values, value)
+			}
+			continue
+		}
+		var value string
+		if cell.Value != nil {
+			value = fmt.Sprint(cell.Value)
+		}
+		values = append(values, value)
+	}
+	return values
+}
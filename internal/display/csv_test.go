@@ -0,0 +1,83 @@
+package display
+
+import (
+	"testing"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+func TestCSVRenderer(t *testing.T) {
+	tests := []struct {
+		name      string
+		renderer  CSVRenderer
+		breakdown bool
+		expected  string
+	}{
+		{
+			name:     "CSV",
+			renderer: CSVRenderer{Comma: ',', Costs: testCosts},
+			expected: "Date,Models,Input,Output,Cache Create 5m,Cache Create 1h,Cache Read,Web Search,Total,Cost (USD)\n" +
+				"2025-06-01,\"Sonnet 4, Opus 4\",1010,100,0,0,0,0,1110,0.75\n" +
+				"2025-06-02,Sonnet 4,0,5,0,0,0,0,5,0.125\n",
+		},
+		{
+			name:     "TSV with totals",
+			renderer: CSVRenderer{Comma: '\t', Totals: true, Costs: testCosts},
+			expected: "Date\tModels\tInput\tOutput\tCache Create 5m\tCache Create 1h\tCache Read\tWeb Search\tTotal\tCost (USD)\n" +
+				"2025-06-01\tSonnet 4, Opus 4\t1010\t100\t0\t0\t0\t0\t1110\t0.75\n" +
+				"2025-06-02\tSonnet 4\t0\t5\t0\t0\t0\t0\t5\t0.125\n" +
+				"TOTAL\t\t1010\t105\t0\t0\t0\t0\t1115\t0.875\n",
+		},
+		{
+			name:      "Breakdown",
+			renderer:  CSVRenderer{Comma: ',', Costs: testCosts},
+			breakdown: true,
+			expected: "Date,Model,Price Period,Service Tier,Long Context,Input,Output,Cache Create 5m,Cache Create 1h,Cache Read,Web Search,Total,Cost (USD)\n" +
+				"2025-06-01,Sonnet 4,,standard,false,1000,100,0,0,0,0,1100,0.5\n" +
+				"2025-06-01,Opus 4,,standard,false,10,0,0,0,0,0,10,0.25\n" +
+				"2025-06-02,Sonnet 4,,standard,false,0,5,0,0,0,0,5,0.125\n",
+		},
+		{
+			name:      "TSV breakdown with totals",
+			renderer:  CSVRenderer{Comma: '\t', Totals: true, Costs: testCosts},
+			breakdown: true,
+			expected: "Date\tModel\tPrice Period\tService Tier\tLong Context\tInput\tOutput\tCache Create 5m\tCache Create 1h\tCache Read\tWeb Search\tTotal\tCost (USD)\n" +
+				"2025-06-01\tSonnet 4\t\tstandard\tfalse\t1000\t100\t0\t0\t0\t0\t1100\t0.5\n" +
+				"2025-06-01\tOpus 4\t\tstandard\tfalse\t10\t0\t0\t0\t0\t0\t10\t0.25\n" +
+				"2025-06-02\tSonnet 4\t\tstandard\tfalse\t0\t5\t0\t0\t0\t0\t5\t0.125\n" +
+				"TOTAL\t\t\t\t\t1010\t105\t0\t0\t0\t0\t1115\t0.875\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := render(t, tt.renderer, testDailyReport(tt.breakdown)); out != tt.expected {
+				t.Errorf("Render() =\n%s\nwant\n%s", out, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCSVRendererBreakdownFields(t *testing.T) {
+	period := usagePeriod{keys: Row{textCell("2025-06-01")}}
+	b := models.ModelBreakdown{
+		Model:       "claude-sonnet-4-20250514",
+		PricePeriod: "from 2025-06-01",
+		LongContext: true,
+		ServiceTier: models.ServiceTierBatch,
+		TokenUsage:  models.TokenUsage{InputTokens: 300000},
+		Cost:        models.USD(1.5),
+	}
+	section := breakdownSection(period, []models.ModelBreakdown{b})
+
+	if name := section.Rows[0][0].Text; name != "Sonnet 4 (from 2025-06-01) [long context] [batch]" {
+		t.Errorf("Table name = %q", name)
+	}
+
+	report := Report{Columns: []Column{{Name: "Date"}}, Sections: []Section{section}}
+	expected := "Date,Model,Price Period,Service Tier,Long Context,Input,Output,Cache Create 5m,Cache Create 1h,Cache Read,Web Search,Total,Cost (USD)\n" +
+		"2025-06-01,Sonnet 4,from 2025-06-01,batch,true,300000,0,0,0,0,0,300000,1.5\n"
+	if out := render(t, CSVRenderer{Comma: ',', Costs: testCosts}, report); out != expected {
+		t.Errorf("Render() =\n%s\nwant\n%s", out, expected)
+	}
+}
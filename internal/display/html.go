@@ -0,0 +1,178 @@
+package display
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+type htmlCell struct {
+	Text    string
+	Sort    string
+	Numeric bool
+}
+
+type htmlColumn struct {
+	Name    string
+	Numeric bool
+}
+
+type htmlTable struct {
+	Columns []htmlColumn
+	Rows    [][]htmlCell
+	Totals  []htmlCell
+}
+
+type htmlSection struct {
+	Title string
+	Table htmlTable
+}
+
+type htmlPage struct {
+	Title    string
+	Intro    []Note
+	Empty    string
+	Summary  htmlTable
+	Sections []htmlSection
+	Notes    []Note
+}
+
+// HTMLRenderer writes a report as a self-contained HTML page: the report
+// table, whose columns sort on click, and a collapsible table per breakdown
+// section.
+type HTMLRenderer struct {
+	Costs CostFormat
+}
+
+func (r HTMLRenderer) Render(w io.Writer, report Report) error {
+	page := htmlPage{
+		Title:   report.Title,
+		Intro:   htmlNotes(r.Costs, report.Intro),
+		Summary: newHTMLTable(r.Costs, report.Columns, report.Rows, report.Totals),
+		Notes:   htmlNotes(r.Costs, report.Notes),
+	}
+	if len(report.Rows) == 0 {
+		page.Empty = report.Empty
+	}
+	for _, section := range report.Sections {
+		page.Sections = append(page.Sections, htmlSection{
+			Title: section.Title,
+			Table: newHTMLTable(r.Costs, section.Columns, section.Rows, nil),
+		})
+	}
+
+	return htmlTemplate.Execute(w, page)
+}
+
+// newHTMLTable keeps the raw value of each cell for sorting.
+func newHTMLTable(costs CostFormat, columns []Column, rows []Row, totals Row) htmlTable {
+	var table htmlTable
+	for _, c := range columns {
+		table.Columns = append(table.Columns, htmlColumn{Name: costs.columnName(c), Numeric: c.Numeric})
+	}
+	for _, row := range rows {
+		table.Rows = append(table.Rows, htmlCells(costs, columns, row))
+	}
+	if totals != nil {
+		table.Totals = htmlCells(costs, columns, totals)
+	}
+	return table
+}
+
+func htmlCells(costs CostFormat, columns []Column, row Row) []htmlCell {
+	cells := make([]htmlCell, len(row))
+	for i, cell := range row {
+		cells[i] = htmlCell{Text: costs.cellText(cell), Numeric: columns[i].Numeric}
+		if cell.Value != nil {
+			cells[i].Sort = fmt.Sprint(cell.Value)
+		}
+	}
+	return cells
+}
+
+// htmlNotes fills in the costs of notes.
+func htmlNotes(costs CostFormat, notes []Note) []Note {
+	filled := make([]Note, len(notes))
+	for i, note := range notes {
+		filled[i] = Note{Label: note.Label, Text: costs.noteText(note)}
+	}
+	return filled
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; }
+th { background: #f6f8fa; cursor: pointer; user-select: none; white-space: nowrap; }
+th[data-dir="asc"]::after { content: " \25B2"; }
+th[data-dir="desc"]::after { content: " \25BC"; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+tfoot td { font-weight: bold; background: #fff8c5; }
+details { margin: 0.5em 0; }
+summary { cursor: pointer; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- range .Intro}}{{if or .Label .Text}}
+<p>{{if .Label}}<strong>{{.Label}}</strong> {{end}}{{.Text}}</p>{{end}}{{end}}
+{{- if .Empty}}
+<p>{{.Empty}}</p>
+{{- else}}
+{{template "table" .Summary}}
+{{- end}}
+{{- if .Sections}}
+<h2>Breakdown by model</h2>
+{{- range .Sections}}
+<details>
+<summary>{{.Title}}</summary>
+{{template "table" .Table}}
+</details>
+{{- end}}
+{{- end}}
+{{- range .Notes}}{{if or .Label .Text}}
+<p>{{if .Label}}<strong>{{.Label}}</strong> {{end}}{{.Text}}</p>{{end}}{{end}}
+<script>
+document.querySelectorAll("table.sortable th").forEach(function (th) {
+  th.addEventListener("click", function () {
+    var table = th.closest("table");
+    var tbody = table.tBodies[0];
+    var index = Array.prototype.indexOf.call(th.parentNode.children, th);
+    var dir = th.dataset.dir === "asc" ? "desc" : "asc";
+    table.querySelectorAll("th").forEach(function (other) { delete other.dataset.dir; });
+    th.dataset.dir = dir;
+    var rows = Array.prototype.slice.call(tbody.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[index].dataset.sort, y = b.cells[index].dataset.sort;
+      var cmp = th.dataset.numeric ? parseFloat(x) - parseFloat(y) : x.localeCompare(y);
+      return dir === "asc" ? cmp : -cmp;
+    });
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+{{define "table"}}
+<table class="sortable">
+<thead>
+<tr>{{range .Columns}}<th{{if .Numeric}} data-numeric="1"{{end}}>{{.Name}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td{{if .Numeric}} class="num"{{end}} data-sort="{{.Sort}}">{{.Text}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+{{- if .Totals}}
+<tfoot>
+<tr>{{range .Totals}}<td{{if .Numeric}} class="num"{{end}}>{{.Text}}</td>{{end}}</tr>
+</tfoot>
+{{- end}}
+</table>
+{{- end}}
+`))
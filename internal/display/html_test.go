@@ -0,0 +1,27 @@
+package display
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLRenderer(t *testing.T) {
+	out := render(t, HTMLRenderer{Costs: testCosts}, testDailyReport(true))
+
+	for _, want := range []string{
+		"<title>Daily Usage</title>",
+		"<style>",
+		`<th data-numeric="1">Cost (USD)</th>`,
+		`<td data-sort="2025-06-01">2025-06-01</td>`,
+		`<td class="num" data-sort="0.75">$0.7500</td>`,
+		"<summary>2025-06-01</summary>",
+		"<td>TOTAL</td>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "<script src") || strings.Contains(out, "<link") {
+		t.Errorf("Render() is not self-contained")
+	}
+}
@@ -0,0 +1,454 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// JSONSchemaVersion is the version of the JSON output schema. It is bumped
+// when a field is removed or changes meaning; adding fields does not bump it.
+const JSONSchemaVersion = 1
+
+// JSONRenderer writes a report's values in the JSON schema, wrapped in an
+// envelope describing how the report was produced. Currency is the code of
+// the report's costs, US dollars if empty.
+type JSONRenderer struct {
+	Metadata JSONMetadata
+	Currency string
+}
+
+// JSONMetadata is the part of the envelope that the report itself does not
+// know about.
+type JSONMetadata struct {
+	GeneratedAt time.Time
+	ToolVersion string
+	Filters     JSONFilters
+	Pricing     JSONPricingSource
+}
+
+type JSONFilters struct {
+	Since        *models.Date `json:"since" desc:"First day included (--since), or null"`
+	Until        *models.Date `json:"until" desc:"Last day included (--until), or null"`
+	Timezone     string       `json:"timezone" desc:"IANA time zone dates are grouped in, or Local for the system time zone"`
+	Models       []string     `json:"models" desc:"Model filters (--models); empty means all models"`
+	Projects     []string     `json:"projects" desc:"Project filters (--project); empty means all projects"`
+	ServiceTiers []string     `json:"service_tiers" desc:"Service tier filters (--service-tier); empty means all tiers"`
+}
+
+type JSONPricingSource struct {
+	CostMode  string  `json:"cost_mode" desc:"Where costs come from: auto, calculate or display"`
+	File      *string `json:"file" desc:"Pricing file merged over the built-in prices, or null"`
+	RatesFile *string `json:"rates_file" desc:"Exchange rate file costs were converted with, or null for USD"`
+}
+
+type jsonDocument struct {
+	SchemaVersion int               `json:"schema_version" desc:"Version of this schema"`
+	Report        string            `json:"report" desc:"Report kind, which determines the shape of rows and totals"`
+	GeneratedAt   time.Time         `json:"generated_at" desc:"When the report was generated"`
+	ToolVersion   string            `json:"tool_version" desc:"Version of claude-usage-go that generated the report"`
+	Currency      string            `json:"currency" desc:"ISO 4217 code of every cost in the report"`
+	Filters       JSONFilters       `json:"filters" desc:"Filters applied to the messages"`
+	Pricing       JSONPricingSource `json:"pricing" desc:"Where prices and costs came from"`
+	Rows          interface{}       `json:"rows" desc:"Report rows, in report order"`
+	Totals        interface{}       `json:"totals" desc:"Report totals, or null if the report has none"`
+}
+
+func (r JSONRenderer) Render(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	filters := r.Metadata.Filters
+	filters.Models = nonNil(filters.Models)
+	filters.Projects = nonNil(filters.Projects)
+	filters.ServiceTiers = nonNil(filters.ServiceTiers)
+
+	var rows, totals interface{}
+	if report.Data != nil {
+		rows, totals = report.Data.jsonData()
+	}
+	return encoder.Encode(jsonDocument{
+		SchemaVersion: JSONSchemaVersion,
+		Report:        report.Kind,
+		GeneratedAt:   r.Metadata.GeneratedAt,
+		ToolVersion:   r.Metadata.ToolVersion,
+		Currency:      CostFormat{Currency: r.Currency}.currency(),
+		Filters:       filters,
+		Pricing:       r.Metadata.Pricing,
+		Rows:          rows,
+		Totals:        totals,
+	})
+}
+
+// nonNil makes empty lists encode as [] rather than null.
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// Report kinds, the values of the report field.
+const (
+	KindDaily       = "daily"
+	KindWeekly      = "weekly"
+	KindMonthly     = "monthly"
+	KindSession     = "session"
+	KindProject     = "project"
+	KindServiceTier = "service_tier"
+	KindBlocks      = "blocks"
+	KindPricing     = "pricing"
+	KindAudit       = "audit"
+	KindPlan        = "plan"
+)
+
+type jsonTokens struct {
+	InputTokens           int `json:"input_tokens" desc:"Uncached input tokens"`
+	OutputTokens          int `json:"output_tokens" desc:"Output tokens"`
+	CacheCreation5mTokens int `json:"cache_creation_5m_tokens" desc:"Tokens written to the 5-minute cache"`
+	CacheCreation1hTokens int `json:"cache_creation_1h_tokens" desc:"Tokens written to the 1-hour cache"`
+	CacheReadTokens       int `json:"cache_read_tokens" desc:"Tokens read from the cache"`
+	WebSearchRequests     int `json:"web_search_requests" desc:"Server-side web searches, which are not tokens"`
+	TotalTokens           int `json:"total_tokens" desc:"Sum of the token counts, excluding web searches"`
+}
+
+func newJSONTokens(usage models.TokenUsage) jsonTokens {
+	return jsonTokens{
+		InputTokens:           usage.InputTokens,
+		OutputTokens:          usage.OutputTokens,
+		CacheCreation5mTokens: usage.CacheCreateTokens,
+		CacheCreation1hTokens: usage.CacheCreate1hTokens,
+		CacheReadTokens:       usage.CacheReadTokens,
+		WebSearchRequests:     usage.WebSearchRequests,
+		TotalTokens:           usage.Total(),
+	}
+}
+
+type jsonUsageTotals struct {
+	jsonTokens
+	Cost models.Money `json:"cost" desc:"Exact cost"`
+}
+
+type jsonUsage struct {
+	Models []string `json:"models" desc:"Full IDs of the models used"`
+	jsonTokens
+	Cost      models.Money     `json:"cost" desc:"Exact cost"`
+	Breakdown []jsonModelUsage `json:"breakdown,omitempty" desc:"Usage per model, present with --breakdown"`
+}
+
+type jsonModelUsage struct {
+	Model       string `json:"model" desc:"Full model ID"`
+	ServiceTier string `json:"service_tier" desc:"standard, batch or priority"`
+	PricePeriod string `json:"price_period,omitempty" desc:"Period of the price applied, if the model's price changed over time"`
+	LongContext bool   `json:"long_context" desc:"Whether long context rates applied"`
+	jsonTokens
+	Cost models.Money `json:"cost" desc:"Exact cost"`
+}
+
+func newJSONUsage(modelList []string, usage models.TokenUsage, cost models.Money, breakdowns []models.ModelBreakdown) jsonUsage {
+	out := jsonUsage{
+		Models:     nonNil(modelList),
+		jsonTokens: newJSONTokens(usage),
+		Cost:       cost,
+	}
+	for _, b := range breakdowns {
+		out.Breakdown = append(out.Breakdown, jsonModelUsage{
+			Model:       b.Model,
+			ServiceTier: b.ServiceTier,
+			PricePeriod: b.PricePeriod,
+			LongContext: b.LongContext,
+			jsonTokens:  newJSONTokens(b.TokenUsage),
+			Cost:        b.Cost,
+		})
+	}
+	return out
+}
+
+type jsonDaily struct {
+	Date string `json:"date" desc:"Day, YYYY-MM-DD"`
+	jsonUsage
+}
+
+type jsonWeekly struct {
+	Week      string `json:"week" desc:"ISO year and week of the week's Monday, YYYY-Www"`
+	StartDate string `json:"start_date" desc:"First day of the week, YYYY-MM-DD"`
+	jsonUsage
+}
+
+type jsonMonthly struct {
+	Month string `json:"month" desc:"Month, YYYY-MM"`
+	jsonUsage
+}
+
+type jsonSession struct {
+	SessionID string    `json:"session_id" desc:"Full session ID"`
+	StartTime time.Time `json:"start_time" desc:"First message of the session"`
+	EndTime   time.Time `json:"end_time" desc:"Last message of the session"`
+	jsonUsage
+}
+
+type jsonProject struct {
+	Project string `json:"project" desc:"Project path"`
+	jsonUsage
+}
+
+type jsonServiceTier struct {
+	ServiceTier string `json:"service_tier" desc:"standard, batch or priority"`
+	jsonUsage
+}
+
+type jsonBlock struct {
+	StartTime    time.Time       `json:"start_time" desc:"Start of the 5-hour block"`
+	EndTime      time.Time       `json:"end_time" desc:"When the block closes"`
+	LastActivity time.Time       `json:"last_activity" desc:"Last message in the block"`
+	IsActive     bool            `json:"is_active" desc:"Whether the block is still open"`
+	Projection   *jsonProjection `json:"projection,omitempty" desc:"Projection of the active block to its end"`
+	jsonUsage
+}
+
+type jsonProjection struct {
+	ElapsedSeconds   int          `json:"elapsed_seconds" desc:"Time since the block started"`
+	RemainingSeconds int          `json:"remaining_seconds" desc:"Time until the block closes"`
+	TokensPerMinute  float64      `json:"tokens_per_minute" desc:"Current burn rate"`
+	CostPerHour      models.Money `json:"cost_per_hour" desc:"Current cost rate"`
+	ProjectedTokens  int          `json:"projected_tokens" desc:"Tokens at the end of the block at the current rate"`
+	ProjectedCost    models.Money `json:"projected_cost" desc:"Cost at the end of the block at the current rate"`
+}
+
+func newJSONProjection(p *models.BlockProjection) *jsonProjection {
+	if p == nil {
+		return nil
+	}
+	return &jsonProjection{
+		ElapsedSeconds:   int(p.Elapsed.Seconds()),
+		RemainingSeconds: int(p.Remaining.Seconds()),
+		TokensPerMinute:  p.TokensPerMinute,
+		CostPerHour:      p.CostPerHour,
+		ProjectedTokens:  p.ProjectedTokens,
+		ProjectedCost:    p.ProjectedCost,
+	}
+}
+
+// jsonPrice lists prices in USD per 1M tokens whatever the report currency,
+// with the defaulted rates filled in.
+type jsonPrice struct {
+	Model  string `json:"model" desc:"Full model ID"`
+	Source string `json:"source" desc:"built-in, or the pricing file the price came from"`
+	models.Pricing
+}
+
+type jsonAuditTotals struct {
+	Messages          int          `json:"messages" desc:"Messages with a recorded cost"`
+	RecordedCost      models.Money `json:"recorded_cost" desc:"Sum of the recorded costs"`
+	CalculatedCost    models.Money `json:"calculated_cost" desc:"Sum of the costs calculated from pricing"`
+	Difference        models.Money `json:"difference" desc:"How much the calculated cost exceeds the recorded one"`
+	DifferencePercent *float64     `json:"difference_percent" desc:"Difference as a percentage of the recorded cost, or null if it is zero"`
+}
+
+type jsonAudit struct {
+	Model string `json:"model" desc:"Full model ID"`
+	jsonAuditTotals
+}
+
+func newJSONAuditTotals(audit models.CostAudit) jsonAuditTotals {
+	out := jsonAuditTotals{
+		Messages:       audit.Messages,
+		RecordedCost:   audit.RecordedCost,
+		CalculatedCost: audit.CalculatedCost,
+		Difference:     audit.Difference,
+	}
+	if audit.RecordedCost != 0 {
+		percent := audit.Difference.Float64() / audit.RecordedCost.Float64() * 100
+		out.DifferencePercent = &percent
+	}
+	return out
+}
+
+type jsonPlanMonth struct {
+	Month           string       `json:"month" desc:"Month, YYYY-MM"`
+	APICost         models.Money `json:"api_cost" desc:"What the month's usage would have cost at API prices"`
+	PlanPrice       models.Money `json:"plan_price" desc:"Price of the plan"`
+	ValueMultiplier float64      `json:"value_multiplier" desc:"API cost divided by plan price"`
+	Savings         models.Money `json:"savings" desc:"API cost minus plan price"`
+	BreakEven       *models.Date `json:"break_even" desc:"Day the API cost reached the plan price, or null"`
+	PeakBlockCost   models.Money `json:"peak_block_cost" desc:"API cost of the busiest 5-hour block"`
+	CheapestPlan    string       `json:"cheapest_plan" desc:"Cheapest option for the month, a plan or API"`
+	CheapestCost    models.Money `json:"cheapest_cost" desc:"Cost of the cheapest option"`
+}
+
+type jsonPlan struct {
+	Name            string       `json:"name" desc:"Plan name"`
+	Price           models.Money `json:"price" desc:"Monthly price, at the exchange rate of the latest month"`
+	UsageMultiplier int          `json:"usage_multiplier" desc:"Usage allowance relative to Pro"`
+}
+
+type jsonPlanTotals struct {
+	Plan            jsonPlan     `json:"plan" desc:"Plan compared with"`
+	APICost         models.Money `json:"api_cost" desc:"API cost over all months"`
+	PlanCost        models.Money `json:"plan_cost" desc:"Plan price over all months"`
+	ValueMultiplier float64      `json:"value_multiplier" desc:"API cost divided by plan cost"`
+	Savings         models.Money `json:"savings" desc:"API cost minus plan cost"`
+	CheapestPlan    string       `json:"cheapest_plan" desc:"Cheapest option over all months, a plan or API"`
+	CheapestCost    models.Money `json:"cheapest_cost" desc:"Cost of the cheapest option"`
+}
+
+func newJSONPlanMonth(month models.PlanMonth) jsonPlanMonth {
+	out := jsonPlanMonth{
+		Month:           fmt.Sprintf("%d-%02d", month.Year, month.Month),
+		APICost:         month.APICost,
+		PlanPrice:       month.PlanPrice,
+		ValueMultiplier: month.ValueMultiplier,
+		Savings:         month.Savings,
+		PeakBlockCost:   month.PeakBlockCost,
+		CheapestPlan:    month.CheapestPlan,
+		CheapestCost:    month.CheapestCost,
+	}
+	if month.BreakEven != nil {
+		out.BreakEven = models.NewDate(month.BreakEven.Date())
+	}
+	return out
+}
+
+func newJSONPlanTotals(comparison models.PlanComparison) jsonPlanTotals {
+	return jsonPlanTotals{
+		Plan: jsonPlan{
+			Name:            comparison.Plan.Name,
+			Price:           comparison.PlanPrice,
+			UsageMultiplier: comparison.Plan.UsageMultiplier,
+		},
+		APICost:         comparison.APICost,
+		PlanCost:        comparison.PlanCost,
+		ValueMultiplier: comparison.ValueMultiplier,
+		Savings:         comparison.Savings,
+		CheapestPlan:    comparison.CheapestPlan,
+		CheapestCost:    comparison.CheapestCost,
+	}
+}
+
+func (d UsageData) jsonTotals() jsonUsageTotals {
+	return jsonUsageTotals{jsonTokens: newJSONTokens(d.Totals.TokenUsage), Cost: d.Totals.Cost}
+}
+
+// breakdown returns the breakdown of row i, or nil if there is none.
+func (d UsageData) breakdown(i int) []models.ModelBreakdown {
+	if i >= len(d.Breakdowns) {
+		return nil
+	}
+	return d.Breakdowns[i]
+}
+
+func (d DailyData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonDaily, 0, len(d.Days))
+	for i, day := range d.Days {
+		rows = append(rows, jsonDaily{
+			Date:      day.Date.Format("2006-01-02"),
+			jsonUsage: newJSONUsage(day.Models, day.TokenUsage, day.Cost, d.breakdown(i)),
+		})
+	}
+	return rows, d.jsonTotals()
+}
+
+func (d WeeklyData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonWeekly, 0, len(d.Weeks))
+	for i, w := range d.Weeks {
+		rows = append(rows, jsonWeekly{
+			Week:      fmt.Sprintf("%d-W%02d", w.Year, w.Week),
+			StartDate: w.StartDate.Format("2006-01-02"),
+			jsonUsage: newJSONUsage(w.Models, w.TokenUsage, w.Cost, d.breakdown(i)),
+		})
+	}
+	return rows, d.jsonTotals()
+}
+
+func (d MonthlyData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonMonthly, 0, len(d.Months))
+	for i, m := range d.Months {
+		rows = append(rows, jsonMonthly{
+			Month:     fmt.Sprintf("%d-%02d", m.Year, m.Month),
+			jsonUsage: newJSONUsage(m.Models, m.TokenUsage, m.Cost, d.breakdown(i)),
+		})
+	}
+	return rows, d.jsonTotals()
+}
+
+func (d SessionData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonSession, 0, len(d.Sessions))
+	for i, s := range d.Sessions {
+		rows = append(rows, jsonSession{
+			SessionID: s.SessionID,
+			StartTime: s.StartTime,
+			EndTime:   s.EndTime,
+			jsonUsage: newJSONUsage(s.Models, s.TokenUsage, s.Cost, d.breakdown(i)),
+		})
+	}
+	return rows, d.jsonTotals()
+}
+
+func (d ProjectData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonProject, 0, len(d.Projects))
+	for i, p := range d.Projects {
+		rows = append(rows, jsonProject{
+			Project:   p.Project,
+			jsonUsage: newJSONUsage(p.Models, p.TokenUsage, p.Cost, d.breakdown(i)),
+		})
+	}
+	return rows, d.jsonTotals()
+}
+
+func (d ServiceTierData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonServiceTier, 0, len(d.Tiers))
+	for i, t := range d.Tiers {
+		rows = append(rows, jsonServiceTier{
+			ServiceTier: t.ServiceTier,
+			jsonUsage:   newJSONUsage(t.Models, t.TokenUsage, t.Cost, d.breakdown(i)),
+		})
+	}
+	return rows, d.jsonTotals()
+}
+
+func (d BlocksData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonBlock, 0, len(d.Blocks))
+	for i, b := range d.Blocks {
+		rows = append(rows, jsonBlock{
+			StartTime:    b.StartTime,
+			EndTime:      b.EndTime,
+			LastActivity: b.LastActivity,
+			IsActive:     b.IsActive,
+			Projection:   newJSONProjection(b.Projection),
+			jsonUsage:    newJSONUsage(b.Models, b.TokenUsage, b.Cost, d.breakdown(i)),
+		})
+	}
+	return rows, d.jsonTotals()
+}
+
+// jsonData fills in the defaulted rates; pricing has no totals.
+func (d PricingData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonPrice, 0, len(d.Entries))
+	for _, entry := range d.Entries {
+		pricing := entry.Pricing
+		pricing.CacheCreate1hPer1M = pricing.CacheCreate1hRate()
+		webSearchRate := pricing.WebSearchRate()
+		pricing.WebSearchPer1K = &webSearchRate
+		rows = append(rows, jsonPrice{Model: entry.Model, Source: entry.Source, Pricing: pricing})
+	}
+	return rows, nil
+}
+
+func (d AuditData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonAudit, 0, len(d.Audits))
+	for _, audit := range d.Audits {
+		rows = append(rows, jsonAudit{Model: audit.Model, jsonAuditTotals: newJSONAuditTotals(audit)})
+	}
+	return rows, newJSONAuditTotals(d.Totals)
+}
+
+func (d PlanData) jsonData() (interface{}, interface{}) {
+	rows := make([]jsonPlanMonth, 0, len(d.Comparison.Months))
+	for _, month := range d.Comparison.Months {
+		rows = append(rows, newJSONPlanMonth(month))
+	}
+	return rows, newJSONPlanTotals(d.Comparison)
+}
@@ -0,0 +1,89 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MarkdownRenderer writes a report as GitHub-flavored Markdown: the report
+// table with its totals row in bold and, below it, a table per breakdown
+// section.
+type MarkdownRenderer struct {
+	Costs CostFormat
+}
+
+func (r MarkdownRenderer) Render(w io.Writer, report Report) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## %s\n\n", report.Title)
+	writeMarkdownNotes(&sb, r.Costs, report.Intro)
+
+	if len(report.Rows) == 0 && report.Empty != "" {
+		sb.WriteString(report.Empty + "\n")
+	} else {
+		writeMarkdownTable(&sb, r.Costs, report.Columns, report.Rows, report.Totals)
+	}
+
+	for _, section := range report.Sections {
+		fmt.Fprintf(&sb, "\n### %s\n\n", escapeMarkdown(section.Title))
+		writeMarkdownTable(&sb, r.Costs, section.Columns, section.Rows, nil)
+	}
+
+	if len(report.Notes) > 0 {
+		sb.WriteString("\n")
+		writeMarkdownNotes(&sb, r.Costs, report.Notes)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeMarkdownTable left-aligns text columns and right-aligns numeric ones.
+func writeMarkdownTable(sb *strings.Builder, costs CostFormat, columns []Column, rows []Row, totals Row) {
+	sb.WriteString("| " + strings.Join(costs.columnNames(columns), " | ") + " |\n")
+	for _, c := range columns {
+		if c.Numeric {
+			sb.WriteString("| ---: ")
+		} else {
+			sb.WriteString("| --- ")
+		}
+	}
+	sb.WriteString("|\n")
+
+	for _, row := range rows {
+		writeMarkdownRow(sb, costs, row, false)
+	}
+	if totals != nil {
+		writeMarkdownRow(sb, costs, totals, true)
+	}
+}
+
+func writeMarkdownRow(sb *strings.Builder, costs CostFormat, row Row, bold bool) {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = escapeMarkdown(costs.cellText(cell))
+		if bold && cells[i] != "" {
+			cells[i] = "**" + cells[i] + "**"
+		}
+	}
+	sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+}
+
+// writeMarkdownNotes writes each note as its own paragraph, skipping blank
+// notes, which only space out the terminal output.
+func writeMarkdownNotes(sb *strings.Builder, costs CostFormat, notes []Note) {
+	for _, note := range notes {
+		text := strings.TrimSpace(costs.noteText(note))
+		switch {
+		case note.Label != "":
+			fmt.Fprintf(sb, "**%s** %s\n\n", note.Label, text)
+		case text != "":
+			sb.WriteString(text + "\n\n")
+		}
+	}
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,53 @@
+package display
+
+import (
+	"testing"
+)
+
+func TestMarkdownRenderer(t *testing.T) {
+	table := "## Daily Usage\n\n" +
+		"| Date | Models | Input | Output | Cache Create 5m | Cache Create 1h | Cache Read | Web Search | Total | Cost (USD) |\n" +
+		"| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n" +
+		"| 2025-06-01 | Sonnet 4, Opus 4 | 1010 | 100 | - | - | - | - | 1110 | $0.7500 |\n" +
+		"| 2025-06-02 | Sonnet 4 | - | 5 | - | - | - | - | 5 | $0.1250 |\n" +
+		"| **TOTAL** |  | **1010** | **105** | **-** | **-** | **-** | **-** | **1115** | **$0.8750** |\n"
+
+	tests := []struct {
+		name      string
+		breakdown bool
+		expected  string
+	}{
+		{
+			name:     "Table with totals",
+			expected: table,
+		},
+		{
+			name:      "Breakdown",
+			breakdown: true,
+			expected: table +
+				"\n### 2025-06-01\n\n" +
+				"| Model | Input | Output | Cache Create 5m | Cache Create 1h | Cache Read | Web Search | Total | Cost (USD) |\n" +
+				"| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n" +
+				"| Sonnet 4 | 1000 | 100 | - | - | - | - | 1100 | $0.5000 |\n" +
+				"| Opus 4 | 10 | - | - | - | - | - | 10 | $0.2500 |\n" +
+				"\n### 2025-06-02\n\n" +
+				"| Model | Input | Output | Cache Create 5m | Cache Create 1h | Cache Read | Web Search | Total | Cost (USD) |\n" +
+				"| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n" +
+				"| Sonnet 4 | - | 5 | - | - | - | - | 5 | $0.1250 |\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := render(t, MarkdownRenderer{Costs: testCosts}, testDailyReport(tt.breakdown)); out != tt.expected {
+				t.Errorf("Render() =\n%s\nwant\n%s", out, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	if got := escapeMarkdown("a|b"); got != `a\|b` {
+		t.Errorf("escapeMarkdown() = %s", got)
+	}
+}
@@ -0,0 +1,197 @@
+package display
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fatih/color"
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// Report is the format-independent form of a report. Every command builds
+// one, and a Renderer writes it out.
+type Report struct {
+	// Kind names the report in JSON output, e.g. KindDaily
+	Kind    string
+	Title   string
+	Columns []Column
+	Rows    []Row
+	// Totals is the totals row, or nil if the report has none
+	Totals Row
+	// Sections are the per-model breakdowns of the rows, if requested
+	Sections []Section
+	// Intro and Notes are lines written before and after the report table
+	Intro []Note
+	Notes []Note
+	// Empty is written instead of the table when there are no rows
+	Empty string
+	// Data are the values behind Rows and Totals, which JSON and templates
+	// are rendered from
+	Data ReportData
+}
+
+// ReportData are the values a report was built from. Each kind of report has
+// its own, which lays itself out for JSON and for templates.
+type ReportData interface {
+	// jsonData returns the rows and totals of the JSON schema of the kind
+	jsonData() (rows, totals interface{})
+	// templateData returns the rows and totals templates are executed on
+	templateData() (rows, totals interface{})
+}
+
+// UsageData is what the data of the usage reports have in common.
+type UsageData struct {
+	// Breakdowns split each row by model; nil without a breakdown
+	Breakdowns [][]models.ModelBreakdown
+	Totals     UsageTotals
+}
+
+type DailyData struct {
+	Days []models.DailyUsage
+	UsageData
+}
+
+type WeeklyData struct {
+	Weeks []models.WeeklyUsage
+	UsageData
+}
+
+type MonthlyData struct {
+	Months []models.MonthlyUsage
+	UsageData
+}
+
+type SessionData struct {
+	Sessions []models.SessionUsage
+	UsageData
+}
+
+type ProjectData struct {
+	Projects []models.ProjectUsage
+	UsageData
+}
+
+type ServiceTierData struct {
+	Tiers []models.ServiceTierUsage
+	UsageData
+}
+
+type BlocksData struct {
+	Blocks []models.BlockUsage
+	UsageData
+}
+
+type PricingData struct {
+	Entries []models.PricingEntry
+}
+
+type AuditData struct {
+	Audits []models.CostAudit
+	Totals models.CostAudit
+}
+
+// PlanData is the plan comparison, with its months in report order.
+type PlanData struct {
+	Comparison models.PlanComparison
+}
+
+// Column is a report column. Cost columns have the currency added to their
+// name when rendered. Fields split the column into one column each in CSV and
+// TSV, which write the Fields of its cells instead of their Value.
+type Column struct {
+	Name    string
+	Numeric bool
+	Cost    bool
+	Fields  []string
+}
+
+// Cell is a value with its text as shown in tables. Value is a string, an int,
+// a float64 or a models.Money, and is what CSV and JSON write. Costs have no
+// text; renderers format them, with a sign if Signed is set. Fields are the
+// cell's values for the Fields of its column.
+type Cell struct {
+	Text   string
+	Value  interface{}
+	Signed bool
+	Fields []interface{}
+}
+
+type Row []Cell
+
+// Section breaks down one row of a report. Keys are the row's key cells, which
+// CSV repeats on every breakdown row.
+type Section struct {
+	Label   string
+	Title   string
+	Keys    Row
+	Columns []Column
+	Rows    []Row
+}
+
+// Note is a line of text, with an optional highlighted label. If Costs are
+// given, Text is a format string with a %s for each of them.
+type Note struct {
+	Label string
+	Text  string
+	Costs []models.Money
+}
+
+// Renderer writes a report in one output format.
+type Renderer interface {
+	Render(w io.Writer, report Report) error
+}
+
+// RenderOptions are the options of the renderers that have any. Totals adds a
+// totals row to CSV and TSV; the other formats always have one. Costs is how
+// costs are written, and Metadata goes into the JSON envelope.
+type RenderOptions struct {
+	Totals   bool
+	Costs    CostFormat
+	Metadata JSONMetadata
+}
+
+// NewRenderer returns the renderer for format. Tables are colored when
+// standard output is a terminal.
+func NewRenderer(format models.OutputFormat, opts RenderOptions) (Renderer, error) {
+	switch format {
+	case models.FormatTable, "":
+		return TableRenderer{Color: !color.NoColor, Costs: opts.Costs}, nil
+	case models.FormatJSON:
+		return JSONRenderer{Metadata: opts.Metadata, Currency: opts.Costs.Currency}, nil
+	case models.FormatCSV:
+		return CSVRenderer{Comma: ',', Totals: opts.Totals, Costs: opts.Costs}, nil
+	case models.FormatTSV:
+		return CSVRenderer{Comma: '\t', Totals: opts.Totals, Costs: opts.Costs}, nil
+	case models.FormatMarkdown:
+		return MarkdownRenderer{Costs: opts.Costs}, nil
+	case models.FormatHTML:
+		return HTMLRenderer{Costs: opts.Costs}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func textCell(s string) Cell {
+	return Cell{Text: s, Value: s}
+}
+
+func numberCell(n int) Cell {
+	return Cell{Text: formatNumber(n), Value: n}
+}
+
+func costCell(cost models.Money) Cell {
+	return Cell{Value: cost}
+}
+
+func signedCostCell(cost models.Money) Cell {
+	return Cell{Value: cost, Signed: true}
+}
+
+// totalsRow starts a totals row with TOTAL followed by blank cells.
+func totalsRow(blank int) Row {
+	row := Row{textCell("TOTAL")}
+	for i := 0; i < blank; i++ {
+		row = append(row, textCell(""))
+	}
+	return row
+}
@@ -0,0 +1,151 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/calculator"
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// testCosts writes costs in US dollars with the default decimals.
+var testCosts = CostFormat{Decimals: DefaultCostDecimals}
+
+// testDailyReport is two days of usage, the first with two models.
+func testDailyReport(breakdown bool) Report {
+	day1 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	messages := []models.Message{
+		{Timestamp: day1.Add(time.Hour), Model: "claude-sonnet-4-20250514", ServiceTier: models.ServiceTierStandard,
+			TokenUsage: models.TokenUsage{InputTokens: 1000, OutputTokens: 100}, RecordedCostUSD: moneyPtr(models.USD(0.5))},
+		{Timestamp: day1.Add(2 * time.Hour), Model: "claude-opus-4-20250514", ServiceTier: models.ServiceTierStandard,
+			TokenUsage: models.TokenUsage{InputTokens: 10}, RecordedCostUSD: moneyPtr(models.USD(0.25))},
+		{Timestamp: day2.Add(time.Hour), Model: "claude-sonnet-4-20250514", ServiceTier: models.ServiceTierStandard,
+			TokenUsage: models.TokenUsage{OutputTokens: 5}, RecordedCostUSD: moneyPtr(models.USD(0.125))},
+	}
+	dailyUsage := []models.DailyUsage{
+		{Date: day1, Models: []string{"claude-sonnet-4-20250514", "claude-opus-4-20250514"},
+			TokenUsage: models.TokenUsage{InputTokens: 1010, OutputTokens: 100}, Cost: models.USD(0.75)},
+		{Date: day2, Models: []string{"claude-sonnet-4-20250514"},
+			TokenUsage: models.TokenUsage{OutputTokens: 5}, Cost: models.USD(0.125)},
+	}
+
+	return DailyReport(dailyUsage, messages, calculator.Calculator{}, false, breakdown)
+}
+
+func moneyPtr(m models.Money) *models.Money {
+	return &m
+}
+
+func render(t *testing.T, renderer Renderer, report Report) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestNewRenderer(t *testing.T) {
+	costs := CostFormat{Currency: "EUR", Decimals: 2}
+	tests := []struct {
+		format   models.OutputFormat
+		expected Renderer
+	}{
+		{format: models.FormatJSON, expected: JSONRenderer{Currency: "EUR"}},
+		{format: models.FormatCSV, expected: CSVRenderer{Comma: ',', Totals: true, Costs: costs}},
+		{format: models.FormatTSV, expected: CSVRenderer{Comma: '\t', Totals: true, Costs: costs}},
+		{format: models.FormatMarkdown, expected: MarkdownRenderer{Costs: costs}},
+		{format: models.FormatHTML, expected: HTMLRenderer{Costs: costs}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			renderer, err := NewRenderer(tt.format, RenderOptions{Totals: true, Costs: costs})
+			if err != nil {
+				t.Fatalf("NewRenderer() error = %v", err)
+			}
+			if !reflect.DeepEqual(renderer, tt.expected) {
+				t.Errorf("NewRenderer() = %#v, want %#v", renderer, tt.expected)
+			}
+		})
+	}
+
+	if _, err := NewRenderer("yaml", RenderOptions{}); err == nil {
+		t.Error("NewRenderer(yaml) expected an error")
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	file := "/etc/claude-usage-go/pricing.yaml"
+	renderer := JSONRenderer{Metadata: JSONMetadata{
+		GeneratedAt: time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC),
+		ToolVersion: "1.2.3",
+		Filters: JSONFilters{
+			Since:    models.NewDate(2025, 6, 1),
+			Timezone: "UTC",
+			Models:   []string{"sonnet"},
+		},
+		Pricing: JSONPricingSource{CostMode: "auto", File: &file},
+	}}
+
+	var out struct {
+		SchemaVersion int    `json:"schema_version"`
+		Report        string `json:"report"`
+		GeneratedAt   string `json:"generated_at"`
+		ToolVersion   string `json:"tool_version"`
+		Currency      string `json:"currency"`
+		Filters       struct {
+			Since        *string  `json:"since"`
+			Until        *string  `json:"until"`
+			Timezone     string   `json:"timezone"`
+			Models       []string `json:"models"`
+			Projects     []string `json:"projects"`
+			ServiceTiers []string `json:"service_tiers"`
+		} `json:"filters"`
+		Pricing struct {
+			CostMode  string  `json:"cost_mode"`
+			File      *string `json:"file"`
+			RatesFile *string `json:"rates_file"`
+		} `json:"pricing"`
+		Rows   []map[string]interface{} `json:"rows"`
+		Totals map[string]interface{}   `json:"totals"`
+	}
+	if err := json.Unmarshal([]byte(render(t, renderer, testDailyReport(true))), &out); err != nil {
+		t.Fatalf("Render() wrote invalid JSON: %v", err)
+	}
+
+	if out.SchemaVersion != JSONSchemaVersion || out.Report != KindDaily || out.ToolVersion != "1.2.3" || out.Currency != "USD" {
+		t.Errorf("envelope = %d %q %q %q", out.SchemaVersion, out.Report, out.ToolVersion, out.Currency)
+	}
+	if out.GeneratedAt != "2025-06-03T12:00:00Z" {
+		t.Errorf("generated_at = %q", out.GeneratedAt)
+	}
+	if out.Filters.Since == nil || *out.Filters.Since != "2025-06-01" || out.Filters.Until != nil {
+		t.Errorf("since, until = %v, %v", out.Filters.Since, out.Filters.Until)
+	}
+	if out.Filters.Projects == nil || len(out.Filters.Projects) != 0 || out.Filters.ServiceTiers == nil {
+		t.Errorf("empty filters should be [], got %v and %v", out.Filters.Projects, out.Filters.ServiceTiers)
+	}
+	if out.Pricing.File == nil || *out.Pricing.File != file || out.Pricing.RatesFile != nil {
+		t.Errorf("pricing = %+v", out.Pricing)
+	}
+
+	if len(out.Rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2", len(out.Rows))
+	}
+	row := out.Rows[0]
+	if row["date"] != "2025-06-01" || row["input_tokens"] != 1010.0 || row["total_tokens"] != 1110.0 || row["cost"] != 0.75 {
+		t.Errorf("rows[0] = %v", row)
+	}
+	if breakdown, ok := row["breakdown"].([]interface{}); !ok || len(breakdown) != 2 {
+		t.Errorf("rows[0].breakdown = %v", row["breakdown"])
+	}
+	if out.Totals["total_tokens"] != 1115.0 || out.Totals["cost"] != 0.875 {
+		t.Errorf("totals = %v", out.Totals)
+	}
+}
@@ -0,0 +1,494 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/calculator"
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+var usageColumns = []Column{
+	{Name: "Input", Numeric: true},
+	{Name: "Output", Numeric: true},
+	{Name: "Cache Create 5m", Numeric: true},
+	{Name: "Cache Create 1h", Numeric: true},
+	{Name: "Cache Read", Numeric: true},
+	{Name: "Web Search", Numeric: true},
+	{Name: "Total", Numeric: true},
+}
+
+// usagePeriod is one row of a usage report, with a filter selecting its
+// messages for the breakdown.
+type usagePeriod struct {
+	keys     Row
+	label    string
+	title    string
+	models   []string
+	usage    models.TokenUsage
+	cost     models.Money
+	includes func(models.Message) bool
+}
+
+func usageCells(usage models.TokenUsage, cost models.Money) Row {
+	return Row{
+		numberCell(usage.InputTokens),
+		numberCell(usage.OutputTokens),
+		numberCell(usage.CacheCreateTokens),
+		numberCell(usage.CacheCreate1hTokens),
+		numberCell(usage.CacheReadTokens),
+		numberCell(usage.WebSearchRequests),
+		numberCell(usage.Total()),
+		costCell(cost),
+	}
+}
+
+func usageReportColumns(textColumns ...string) []Column {
+	var columns []Column
+	for _, name := range textColumns {
+		columns = append(columns, Column{Name: name})
+	}
+	columns = append(columns, usageColumns...)
+	return append(columns, Column{Name: "Cost", Numeric: true, Cost: true})
+}
+
+// buildUsageReport lays out periods under keyColumns followed by the models,
+// token and cost columns, with a totals row and, with breakdown, a section
+// per period splitting it by model. It returns the breakdowns and totals for
+// the report's data.
+func buildUsageReport(kind, title string, keyColumns []string, periods []usagePeriod, messages []models.Message, calc calculator.Calculator, breakdown bool) (Report, UsageData) {
+	report := Report{
+		Kind:    kind,
+		Title:   title,
+		Columns: usageReportColumns(append(append([]string{}, keyColumns...), "Models")...),
+	}
+
+	var data UsageData
+	var totalUsage models.TokenUsage
+	var totalCost models.Money
+
+	for _, period := range periods {
+		row := append(Row{}, period.keys...)
+		row = append(row, textCell(strings.Join(getShortModelNames(period.models), ", ")))
+		report.Rows = append(report.Rows, append(row, usageCells(period.usage, period.cost)...))
+
+		totalUsage.Add(period.usage)
+		totalCost += period.cost
+
+		if breakdown {
+			breakdowns := periodBreakdown(calc, period, messages)
+			data.Breakdowns = append(data.Breakdowns, breakdowns)
+			report.Sections = append(report.Sections, breakdownSection(period, breakdowns))
+		}
+	}
+
+	report.Totals = append(totalsRow(len(keyColumns)), usageCells(totalUsage, totalCost)...)
+	data.Totals = UsageTotals{TokenUsage: totalUsage, Cost: totalCost}
+	return report, data
+}
+
+func periodBreakdown(calc calculator.Calculator, period usagePeriod, messages []models.Message) []models.ModelBreakdown {
+	var periodMessages []models.Message
+	for _, msg := range messages {
+		if period.includes(msg) {
+			periodMessages = append(periodMessages, msg)
+		}
+	}
+	return calc.AggregateByModel(periodMessages)
+}
+
+func breakdownSection(period usagePeriod, breakdowns []models.ModelBreakdown) Section {
+	section := Section{
+		Label:   period.label,
+		Title:   period.title,
+		Keys:    period.keys,
+		Columns: usageReportColumns("Model"),
+	}
+	section.Columns[0].Fields = []string{"Model", "Price Period", "Service Tier", "Long Context"}
+	for _, b := range breakdowns {
+		name := textCell(breakdownName(b))
+		name.Fields = []interface{}{models.GetModelShortName(b.Model), b.PricePeriod, b.ServiceTier, b.LongContext}
+		section.Rows = append(section.Rows, append(Row{name}, usageCells(b.TokenUsage, b.Cost)...))
+	}
+	return section
+}
+
+func DailyReport(dailyUsage []models.DailyUsage, messages []models.Message, calc calculator.Calculator, ascending, breakdown bool) Report {
+	sortDaily(dailyUsage, ascending)
+
+	periods := make([]usagePeriod, 0, len(dailyUsage))
+	for _, daily := range dailyUsage {
+		date := daily.Date.Format("2006-01-02")
+		periods = append(periods, usagePeriod{
+			keys:   Row{textCell(date)},
+			label:  "Date:",
+			title:  date,
+			models: daily.Models,
+			usage:  daily.TokenUsage,
+			cost:   daily.Cost,
+			includes: func(msg models.Message) bool {
+				return msg.Timestamp.Format("2006-01-02") == date
+			},
+		})
+	}
+
+	report, data := buildUsageReport(KindDaily, "Daily Usage", []string{"Date"}, periods, messages, calc, breakdown)
+	report.Data = DailyData{Days: dailyUsage, UsageData: data}
+	return report
+}
+
+func WeeklyReport(weeklyUsage []models.WeeklyUsage, messages []models.Message, calc calculator.Calculator, ascending, breakdown bool) Report {
+	sortWeekly(weeklyUsage, ascending)
+
+	periods := make([]usagePeriod, 0, len(weeklyUsage))
+	for _, weekly := range weeklyUsage {
+		start := weekly.StartDate
+		end := start.AddDate(0, 0, 7)
+		week := fmt.Sprintf("%d-W%02d", weekly.Year, weekly.Week)
+		periods = append(periods, usagePeriod{
+			keys:   Row{textCell(week), textCell(start.Format("2006-01-02"))},
+			label:  "Week:",
+			title:  fmt.Sprintf("%s (%s - %s)", week, start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02")),
+			models: weekly.Models,
+			usage:  weekly.TokenUsage,
+			cost:   weekly.Cost,
+			includes: func(msg models.Message) bool {
+				return !msg.Timestamp.Before(start) && msg.Timestamp.Before(end)
+			},
+		})
+	}
+
+	report, data := buildUsageReport(KindWeekly, "Weekly Usage", []string{"Week", "Start Date"}, periods, messages, calc, breakdown)
+	report.Data = WeeklyData{Weeks: weeklyUsage, UsageData: data}
+	return report
+}
+
+func MonthlyReport(monthlyUsage []models.MonthlyUsage, messages []models.Message, calc calculator.Calculator, ascending, breakdown bool) Report {
+	sortMonthly(monthlyUsage, ascending)
+
+	periods := make([]usagePeriod, 0, len(monthlyUsage))
+	for _, monthly := range monthlyUsage {
+		year, month := monthly.Year, monthly.Month
+		key := fmt.Sprintf("%d-%02d", year, month)
+		periods = append(periods, usagePeriod{
+			keys:   Row{textCell(key)},
+			label:  "Month:",
+			title:  key,
+			models: monthly.Models,
+			usage:  monthly.TokenUsage,
+			cost:   monthly.Cost,
+			includes: func(msg models.Message) bool {
+				return msg.Timestamp.Year() == year && msg.Timestamp.Month() == month
+			},
+		})
+	}
+
+	report, data := buildUsageReport(KindMonthly, "Monthly Usage", []string{"Month"}, periods, messages, calc, breakdown)
+	report.Data = MonthlyData{Months: monthlyUsage, UsageData: data}
+	return report
+}
+
+// SessionReport shortens session IDs in the text of its cells; CSV and JSON
+// get them in full.
+func SessionReport(sessionUsage []models.SessionUsage, messages []models.Message, calc calculator.Calculator, ascending, breakdown bool) Report {
+	sortSessions(sessionUsage, ascending)
+
+	periods := make([]usagePeriod, 0, len(sessionUsage))
+	for _, session := range sessionUsage {
+		id := session.SessionID
+		periods = append(periods, usagePeriod{
+			keys: Row{
+				{Text: id[:8] + "...", Value: id},
+				textCell(session.StartTime.Format("2006-01-02 15:04")),
+			},
+			label: "Session:",
+			title: fmt.Sprintf("%s... (%s - %s)", id[:16],
+				session.StartTime.Format("2006-01-02 15:04"), session.EndTime.Format("15:04")),
+			models: session.Models,
+			usage:  session.TokenUsage,
+			cost:   session.Cost,
+			includes: func(msg models.Message) bool {
+				return msg.SessionID == id
+			},
+		})
+	}
+
+	report, data := buildUsageReport(KindSession, "Session Usage", []string{"Session ID", "Start Time"}, periods, messages, calc, breakdown)
+	report.Data = SessionData{Sessions: sessionUsage, UsageData: data}
+	return report
+}
+
+func ProjectReport(projectUsage []models.ProjectUsage, messages []models.Message, calc calculator.Calculator, ascending, breakdown bool) Report {
+	sortProjects(projectUsage, ascending)
+
+	periods := make([]usagePeriod, 0, len(projectUsage))
+	for _, project := range projectUsage {
+		name := project.Project
+		periods = append(periods, usagePeriod{
+			keys:   Row{textCell(formatProject(name))},
+			label:  "Project:",
+			title:  formatProject(name),
+			models: project.Models,
+			usage:  project.TokenUsage,
+			cost:   project.Cost,
+			includes: func(msg models.Message) bool {
+				return msg.Project == name
+			},
+		})
+	}
+
+	report, data := buildUsageReport(KindProject, "Project Usage", []string{"Project"}, periods, messages, calc, breakdown)
+	report.Data = ProjectData{Projects: projectUsage, UsageData: data}
+	return report
+}
+
+// ServiceTierReport lists tiers in the order given; it ignores --asc since
+// tiers have no natural time order.
+func ServiceTierReport(tierUsage []models.ServiceTierUsage, messages []models.Message, calc calculator.Calculator, breakdown bool) Report {
+	periods := make([]usagePeriod, 0, len(tierUsage))
+	for _, tier := range tierUsage {
+		name := tier.ServiceTier
+		periods = append(periods, usagePeriod{
+			keys:   Row{textCell(name)},
+			label:  "Service Tier:",
+			title:  name,
+			models: tier.Models,
+			usage:  tier.TokenUsage,
+			cost:   tier.Cost,
+			includes: func(msg models.Message) bool {
+				return models.NormalizeServiceTier(msg.ServiceTier) == name
+			},
+		})
+	}
+
+	report, data := buildUsageReport(KindServiceTier, "Service Tier Usage", []string{"Service Tier"}, periods, messages, calc, breakdown)
+	report.Data = ServiceTierData{Tiers: tierUsage, UsageData: data}
+	return report
+}
+
+// BlocksReport marks the active block and adds its projection as notes.
+func BlocksReport(blockUsage []models.BlockUsage, messages []models.Message, calc calculator.Calculator, ascending, breakdown bool) Report {
+	sortBlocks(blockUsage, ascending)
+
+	var active *models.BlockUsage
+	periods := make([]usagePeriod, 0, len(blockUsage))
+	for i, block := range blockUsage {
+		start, end := block.StartTime, block.EndTime
+		endCell := textCell(end.Format("2006-01-02 15:04"))
+		if block.IsActive {
+			endCell.Text += " (active)"
+			active = &blockUsage[i]
+		}
+		periods = append(periods, usagePeriod{
+			keys:   Row{textCell(start.Format("2006-01-02 15:04")), endCell},
+			label:  "Block:",
+			title:  start.Format("2006-01-02 15:04") + " - " + end.Format("15:04"),
+			models: block.Models,
+			usage:  block.TokenUsage,
+			cost:   block.Cost,
+			includes: func(msg models.Message) bool {
+				return !msg.Timestamp.Before(start) && msg.Timestamp.Before(end)
+			},
+		})
+	}
+
+	report, data := buildUsageReport(KindBlocks, "Billing Blocks", []string{"Block Start", "Block End"}, periods, messages, calc, breakdown)
+	report.Data = BlocksData{Blocks: blockUsage, UsageData: data}
+	if active != nil && active.Projection != nil {
+		report.Notes = blockProjectionNotes(*active)
+	}
+	return report
+}
+
+func blockProjectionNotes(block models.BlockUsage) []Note {
+	p := block.Projection
+	return []Note{
+		{},
+		{Label: "Active block:", Text: block.StartTime.Format("2006-01-02 15:04") + " - " + block.EndTime.Format("15:04")},
+		{Text: fmt.Sprintf("  %-18s %s", "Elapsed:", formatDuration(p.Elapsed))},
+		{Text: fmt.Sprintf("  %-18s %s", "Remaining:", formatDuration(p.Remaining))},
+		{Text: fmt.Sprintf("  %-18s %.0f tokens/min, %%s/hour", "Burn rate:", p.TokensPerMinute), Costs: []models.Money{p.CostPerHour}},
+		{Text: fmt.Sprintf("  %-18s %s", "Projected tokens:", formatNumber(p.ProjectedTokens))},
+		{Text: fmt.Sprintf("  %-18s %%s", "Projected cost:"), Costs: []models.Money{p.ProjectedCost}},
+	}
+}
+
+func PricingReport(entries []models.PricingEntry) Report {
+	report := Report{
+		Kind:  KindPricing,
+		Title: "Model Pricing",
+		Columns: []Column{
+			{Name: "Model"},
+			{Name: "Period"},
+			{Name: "Input", Numeric: true},
+			{Name: "Output", Numeric: true},
+			{Name: "Cache Create 5m", Numeric: true},
+			{Name: "Cache Create 1h", Numeric: true},
+			{Name: "Cache Read", Numeric: true},
+			{Name: "Web Search /1K", Numeric: true},
+			{Name: "Source"},
+		},
+		Data: PricingData{Entries: entries},
+	}
+
+	for _, entry := range entries {
+		period := Cell{Text: "-", Value: ""}
+		if p := entry.Pricing.Period(); p != "" {
+			period = textCell(p)
+		}
+		report.Rows = append(report.Rows, pricingRow(textCell(entry.Model), period, entry.Pricing, entry.Source))
+		if entry.Pricing.LongContext != nil {
+			name := fmt.Sprintf("%s (>%s prompt)", entry.Model, formatNumber(entry.Pricing.LongContext.ThresholdTokens()))
+			report.Rows = append(report.Rows, pricingRow(textCell(name), period, entry.Pricing.LongContextRates(), entry.Source))
+		}
+	}
+
+	tiers := make([]string, 0, len(models.ServiceTierMultipliers))
+	for tier := range models.ServiceTierMultipliers {
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+	for i, tier := range tiers {
+		tiers[i] = fmt.Sprintf("%s %gx", tier, models.ServiceTierMultipliers[tier])
+	}
+	report.Notes = []Note{
+		{Text: "Prices are in USD per 1M tokens, and per 1,000 requests for web search."},
+		{Text: "Service tier multipliers: " + strings.Join(tiers, ", ")},
+	}
+	return report
+}
+
+func pricingRow(name, period Cell, pricing models.Pricing, source string) Row {
+	return Row{
+		name,
+		period,
+		priceCell(pricing.InputPer1M),
+		priceCell(pricing.OutputPer1M),
+		priceCell(pricing.CacheCreatePer1M),
+		priceCell(pricing.CacheCreate1hRate()),
+		priceCell(pricing.CacheReadPer1M),
+		priceCell(pricing.WebSearchRate()),
+		textCell(source),
+	}
+}
+
+func priceCell(price float64) Cell {
+	return Cell{Text: formatPrice(price), Value: price}
+}
+
+func CostAuditReport(audits []models.CostAudit) Report {
+	report := Report{
+		Kind:  KindAudit,
+		Title: "Cost Audit",
+		Columns: []Column{
+			{Name: "Model"},
+			{Name: "Messages", Numeric: true},
+			{Name: "Recorded", Numeric: true, Cost: true},
+			{Name: "Calculated", Numeric: true, Cost: true},
+			{Name: "Difference", Numeric: true, Cost: true},
+			{Name: "Difference (%)", Numeric: true},
+		},
+		Empty: "No messages with a recorded cost found.",
+	}
+
+	var total models.CostAudit
+	for _, audit := range audits {
+		report.Rows = append(report.Rows, auditRow(textCell(models.GetModelShortName(audit.Model)), audit))
+
+		total.Messages += audit.Messages
+		total.RecordedCost += audit.RecordedCost
+		total.CalculatedCost += audit.CalculatedCost
+		total.Difference += audit.Difference
+	}
+	report.Totals = auditRow(textCell("TOTAL"), total)
+	report.Data = AuditData{Audits: audits, Totals: total}
+
+	return report
+}
+
+func auditRow(name Cell, audit models.CostAudit) Row {
+	percent := Cell{Text: formatPercent(audit), Value: ""}
+	if audit.RecordedCost != 0 {
+		percent.Value = audit.Difference.Float64() / audit.RecordedCost.Float64() * 100
+	}
+	return Row{
+		name,
+		numberCell(audit.Messages),
+		costCell(audit.RecordedCost),
+		costCell(audit.CalculatedCost),
+		signedCostCell(audit.Difference),
+		percent,
+	}
+}
+
+func PlanReport(comparison models.PlanComparison, ascending bool) Report {
+	months := append([]models.PlanMonth(nil), comparison.Months...)
+	if ascending {
+		sort.Slice(months, func(i, j int) bool {
+			if months[i].Year != months[j].Year {
+				return months[i].Year > months[j].Year
+			}
+			return months[i].Month > months[j].Month
+		})
+	}
+
+	report := Report{
+		Kind:  KindPlan,
+		Title: "Plan Comparison",
+		Columns: []Column{
+			{Name: "Month"},
+			{Name: "API Cost", Numeric: true, Cost: true},
+			{Name: "Plan Price", Numeric: true, Cost: true},
+			{Name: "Value", Numeric: true},
+			{Name: "Savings", Numeric: true, Cost: true},
+			{Name: "Break-even"},
+			{Name: "Peak Block", Numeric: true, Cost: true},
+			{Name: "Cheapest"},
+		},
+		Intro: []Note{
+			{Label: "Plan:", Text: comparison.Plan.Name + " (%s/month)", Costs: []models.Money{comparison.PlanPrice}},
+			{},
+		},
+	}
+	comparison.Months = months
+	report.Data = PlanData{Comparison: comparison}
+
+	for _, month := range months {
+		breakEven := Cell{Text: "-", Value: ""}
+		if month.BreakEven != nil {
+			breakEven = textCell(month.BreakEven.Format("2006-01-02"))
+		}
+		report.Rows = append(report.Rows, Row{
+			textCell(fmt.Sprintf("%d-%02d", month.Year, month.Month)),
+			costCell(month.APICost),
+			costCell(month.PlanPrice),
+			multiplierCell(month.ValueMultiplier),
+			signedCostCell(month.Savings),
+			breakEven,
+			costCell(month.PeakBlockCost),
+			textCell(month.CheapestPlan),
+		})
+	}
+
+	report.Totals = Row{
+		textCell("TOTAL"),
+		costCell(comparison.APICost),
+		costCell(comparison.PlanCost),
+		multiplierCell(comparison.ValueMultiplier),
+		signedCostCell(comparison.Savings),
+		textCell(""),
+		textCell(""),
+		textCell(comparison.CheapestPlan),
+	}
+	report.Notes = []Note{
+		{Label: "Cheapest option:", Text: fmt.Sprintf("%s at %%s over %d month(s)", comparison.CheapestPlan, len(comparison.Months)),
+			Costs: []models.Money{comparison.CheapestCost}},
+	}
+
+	return report
+}
+
+func multiplierCell(multiplier float64) Cell {
+	return Cell{Text: fmt.Sprintf("%.2f×", multiplier), Value: multiplier}
+}
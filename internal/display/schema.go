@@ -0,0 +1,149 @@
+package display
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// jsonSchemaReports lists the row and totals types of each report kind.
+// Totals types are shared between kinds under totalsDef.
+var jsonSchemaReports = []struct {
+	kind      string
+	row       interface{}
+	rowDesc   string
+	totals    interface{}
+	totalsDef string
+}{
+	{KindDaily, jsonDaily{}, "Usage of a day", jsonUsageTotals{}, "usage_totals"},
+	{KindWeekly, jsonWeekly{}, "Usage of a week", jsonUsageTotals{}, "usage_totals"},
+	{KindMonthly, jsonMonthly{}, "Usage of a month", jsonUsageTotals{}, "usage_totals"},
+	{KindSession, jsonSession{}, "Usage of a session", jsonUsageTotals{}, "usage_totals"},
+	{KindProject, jsonProject{}, "Usage of a project", jsonUsageTotals{}, "usage_totals"},
+	{KindServiceTier, jsonServiceTier{}, "Usage of a service tier", jsonUsageTotals{}, "usage_totals"},
+	{KindBlocks, jsonBlock{}, "Usage of a 5-hour billing block", jsonUsageTotals{}, "usage_totals"},
+	{KindPricing, jsonPrice{}, "Price of a model in USD per 1M tokens, and per 1,000 web searches", nil, ""},
+	{KindAudit, jsonAudit{}, "Recorded and calculated costs of a model", jsonAuditTotals{}, "audit_totals"},
+	{KindPlan, jsonPlanMonth{}, "A month of usage compared with the plan", jsonPlanTotals{}, "plan_totals"},
+}
+
+// JSONSchema returns the JSON Schema of JSON output, generated from the types
+// it is encoded from so that the two cannot drift apart.
+func JSONSchema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(jsonDocument{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "claude-usage-go report"
+	schema["description"] = "JSON output of claude-usage-go. Costs are exact decimal amounts in the report currency; rows and totals depend on the report kind."
+
+	properties := schema["properties"].(map[string]interface{})
+	properties["schema_version"].(map[string]interface{})["const"] = JSONSchemaVersion
+
+	defs := map[string]interface{}{}
+	var kinds []string
+	var cases []interface{}
+	for _, report := range jsonSchemaReports {
+		kinds = append(kinds, report.kind)
+
+		rowDef := report.kind + "_row"
+		defs[rowDef] = schemaFor(reflect.TypeOf(report.row))
+		defs[rowDef].(map[string]interface{})["description"] = report.rowDesc
+
+		totals := map[string]interface{}{"type": "null"}
+		if report.totals != nil {
+			defs[report.totalsDef] = schemaFor(reflect.TypeOf(report.totals))
+			totals = map[string]interface{}{"$ref": "#/$defs/" + report.totalsDef}
+		}
+
+		cases = append(cases, map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"report": map[string]interface{}{"const": report.kind}},
+			},
+			"then": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"rows":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/" + rowDef}},
+					"totals": totals,
+				},
+			},
+		})
+	}
+	properties["report"].(map[string]interface{})["enum"] = kinds
+	schema["$defs"] = defs
+	schema["allOf"] = cases
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+var (
+	moneyType = reflect.TypeOf(models.Money(0))
+	timeType  = reflect.TypeOf(time.Time{})
+	dateType  = reflect.TypeOf(models.Date{})
+)
+
+// schemaFor describes how encoding/json writes a value of type t. Struct
+// fields are required unless omitempty, and pointers may be null.
+func schemaFor(t reflect.Type) map[string]interface{} {
+	switch t {
+	case moneyType:
+		return map[string]interface{}{"type": "number"}
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case dateType:
+		return map[string]interface{}{"type": "string", "format": "date"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := schemaFor(t.Elem())
+		schema["type"] = []interface{}{schema["type"], "null"}
+		return schema
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		required := []string{}
+		addFields(t, properties, &required)
+		return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// addFields adds the fields of struct t to properties, flattening embedded
+// structs the way encoding/json does.
+func addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			addFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		schema := schemaFor(field.Type)
+		if desc := field.Tag.Get("desc"); desc != "" {
+			schema["description"] = desc
+		}
+		properties[name] = schema
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
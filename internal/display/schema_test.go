@@ -0,0 +1,141 @@
+package display
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("JSONSchema() is not valid JSON: %v", err)
+	}
+
+	breakEven := time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)
+	reports := []Report{
+		testDailyReport(true),
+		PricingReport(models.EffectivePricing()),
+		CostAuditReport([]models.CostAudit{
+			{Model: "claude-opus-4-20250514", Messages: 2, RecordedCost: models.USD(1), CalculatedCost: models.USD(1.5), Difference: models.USD(0.5)},
+		}),
+		PlanReport(models.PlanComparison{
+			Plan:   models.SubscriptionPlans[0],
+			Months: []models.PlanMonth{{Year: 2025, Month: time.June, BreakEven: &breakEven, CheapestPlan: "Pro"}},
+		}, false),
+	}
+
+	renderer := JSONRenderer{Metadata: JSONMetadata{Filters: JSONFilters{Timezone: "UTC"}, Pricing: JSONPricingSource{CostMode: "auto"}}}
+	for _, report := range reports {
+		t.Run(report.Kind, func(t *testing.T) {
+			var out map[string]interface{}
+			if err := json.Unmarshal([]byte(render(t, renderer, report)), &out); err != nil {
+				t.Fatalf("Render() wrote invalid JSON: %v", err)
+			}
+			checkSchema(t, "", schemaForKind(t, schema, report.Kind), schema, out)
+		})
+	}
+}
+
+// schemaForKind applies the schema's if/then case for kind to its envelope.
+func schemaForKind(t *testing.T, schema map[string]interface{}, kind string) map[string]interface{} {
+	t.Helper()
+	properties := map[string]interface{}{}
+	for name, property := range schema["properties"].(map[string]interface{}) {
+		properties[name] = property
+	}
+	for _, c := range schema["allOf"].([]interface{}) {
+		c := c.(map[string]interface{})
+		report := c["if"].(map[string]interface{})["properties"].(map[string]interface{})["report"].(map[string]interface{})
+		if report["const"] == kind {
+			for name, property := range c["then"].(map[string]interface{})["properties"].(map[string]interface{}) {
+				properties[name] = property
+			}
+			return map[string]interface{}{"type": "object", "properties": properties, "required": schema["required"]}
+		}
+	}
+	t.Fatalf("schema has no case for report %q", kind)
+	return nil
+}
+
+// checkSchema checks the types, properties and required properties of value
+// against the subset of JSON Schema that JSONSchema generates.
+func checkSchema(t *testing.T, path string, schema, root map[string]interface{}, value interface{}) {
+	t.Helper()
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = root["$defs"].(map[string]interface{})[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+	}
+
+	if !schemaAllows(schema["type"], value) {
+		t.Errorf("%s: %v does not have type %v", path, value, schema["type"])
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range value {
+			propertySchema, ok := properties[name].(map[string]interface{})
+			if !ok {
+				t.Errorf("%s.%s is not in the schema", path, name)
+				continue
+			}
+			checkSchema(t, path+"."+name, propertySchema, root, property)
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := value[name.(string)]; !ok {
+				t.Errorf("%s.%s is required but missing", path, name)
+			}
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for _, item := range value {
+			checkSchema(t, path+"[]", items, root, item)
+		}
+	}
+}
+
+func schemaAllows(schemaType, value interface{}) bool {
+	if types, ok := schemaType.([]interface{}); ok {
+		for _, st := range types {
+			if schemaAllows(st, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch schemaType {
+	case nil:
+		return true
+	case "null":
+		return value == nil
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package display
+
+import (
+	"sort"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// The sort helpers order reports the way --asc expects: the aggregates come
+// oldest first, and ascending reverses them to newest first. Sessions are the
+// exception and come newest first unless ascending is set.
+
+func sortDaily(dailyUsage []models.DailyUsage, ascending bool) {
+	if ascending {
+		sort.Slice(dailyUsage, func(i, j int) bool {
+			return dailyUsage[i].Date.After(dailyUsage[j].Date)
+		})
+	}
+}
+
+func sortWeekly(weeklyUsage []models.WeeklyUsage, ascending bool) {
+	if ascending {
+		sort.Slice(weeklyUsage, func(i, j int) bool {
+			return weeklyUsage[i].StartDate.After(weeklyUsage[j].StartDate)
+		})
+	}
+}
+
+func sortMonthly(monthlyUsage []models.MonthlyUsage, ascending bool) {
+	if ascending {
+		sort.Slice(monthlyUsage, func(i, j int) bool {
+			if monthlyUsage[i].Year != monthlyUsage[j].Year {
+				return monthlyUsage[i].Year > monthlyUsage[j].Year
+			}
+			return monthlyUsage[i].Month > monthlyUsage[j].Month
+		})
+	}
+}
+
+func sortSessions(sessionUsage []models.SessionUsage, ascending bool) {
+	if !ascending {
+		sort.Slice(sessionUsage, func(i, j int) bool {
+			return sessionUsage[i].StartTime.After(sessionUsage[j].StartTime)
+		})
+	}
+}
+
+func sortProjects(projectUsage []models.ProjectUsage, ascending bool) {
+	if ascending {
+		sort.Slice(projectUsage, func(i, j int) bool {
+			return projectUsage[i].Project > projectUsage[j].Project
+		})
+	}
+}
+
+func sortBlocks(blockUsage []models.BlockUsage, ascending bool) {
+	if ascending {
+		sort.Slice(blockUsage, func(i, j int) bool {
+			return blockUsage[i].StartTime.After(blockUsage[j].StartTime)
+		})
+	}
+}
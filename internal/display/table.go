@@ -2,349 +2,235 @@ package display
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
-	"github.com/t-ishitsuka/claude-usage-go/internal/calculator"
 	"github.com/t-ishitsuka/claude-usage-go/internal/models"
 )
 
 var (
 	headerColor = color.New(color.FgCyan, color.Bold)
-	totalColor  = color.New(color.FgYellow, color.Bold)
 	modelColor  = color.New(color.FgGreen)
 	costColor   = color.New(color.FgRed)
 )
 
-func ShowDaily(dailyUsage []models.DailyUsage, ascending bool) error {
-	if ascending {
-		sort.Slice(dailyUsage, func(i, j int) bool {
-			return dailyUsage[i].Date.After(dailyUsage[j].Date)
-		})
-	}
+// DefaultCostDecimals is the number of decimal places costs are rounded to.
+const DefaultCostDecimals = 4
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Date", "Models", "Input", "Output", "Cache Create", "Cache Read", "Total", "Cost (USD)"})
-	table.SetBorder(true)
-	table.SetRowLine(true)
-	table.SetCenterSeparator("+")
-	table.SetColumnSeparator("│")
-	table.SetRowSeparator("─")
-	table.SetHeaderColor(
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-	)
-
-	var totalUsage models.TokenUsage
-	var totalCost float64
-
-	for _, daily := range dailyUsage {
-		modelNames := getShortModelNames(daily.Models)
-		table.Append([]string{
-			daily.Date.Format("2006-01-02"),
-			strings.Join(modelNames, ", "),
-			formatNumber(daily.TokenUsage.InputTokens),
-			formatNumber(daily.TokenUsage.OutputTokens),
-			formatNumber(daily.TokenUsage.CacheCreateTokens),
-			formatNumber(daily.TokenUsage.CacheReadTokens),
-			formatNumber(daily.TokenUsage.Total()),
-			fmt.Sprintf("$%.4f", daily.CostUSD),
-		})
-
-		totalUsage.InputTokens += daily.TokenUsage.InputTokens
-		totalUsage.OutputTokens += daily.TokenUsage.OutputTokens
-		totalUsage.CacheCreateTokens += daily.TokenUsage.CacheCreateTokens
-		totalUsage.CacheReadTokens += daily.TokenUsage.CacheReadTokens
-		totalCost += daily.CostUSD
-	}
+// CostFormat is how costs are written: rounded to Decimals places and shown
+// in Currency, or US dollars if it is empty. It does not convert anything;
+// costs are converted by the calculator.
+type CostFormat struct {
+	Currency string
+	Decimals int
+}
 
-	table.SetFooter([]string{
-		"TOTAL", "",
-		formatNumber(totalUsage.InputTokens),
-		formatNumber(totalUsage.OutputTokens),
-		formatNumber(totalUsage.CacheCreateTokens),
-		formatNumber(totalUsage.CacheReadTokens),
-		formatNumber(totalUsage.Total()),
-		fmt.Sprintf("$%.4f", totalCost),
-	})
-	table.SetFooterColor(
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-	)
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"JPY": "¥",
+	"GBP": "£",
+}
 
-	table.Render()
-	return nil
+// TableRenderer writes a report as bordered terminal tables, with any
+// breakdown sections above the report table. Color adds ANSI colors.
+type TableRenderer struct {
+	Color bool
+	Costs CostFormat
 }
 
-func ShowDailyWithBreakdown(dailyUsage []models.DailyUsage, messages []models.Message, ascending bool) error {
-	if ascending {
-		sort.Slice(dailyUsage, func(i, j int) bool {
-			return dailyUsage[i].Date.After(dailyUsage[j].Date)
-		})
+func (r TableRenderer) Render(w io.Writer, report Report) error {
+	if len(report.Rows) == 0 && report.Empty != "" {
+		_, err := fmt.Fprintln(w, report.Empty)
+		return err
 	}
 
-	for _, daily := range dailyUsage {
-		fmt.Printf("\n%s %s\n", headerColor.Sprint("Date:"), daily.Date.Format("2006-01-02"))
+	r.writeNotes(w, report.Intro)
 
-		var dayMessages []models.Message
-		for _, msg := range messages {
-			if msg.Timestamp.Format("2006-01-02") == daily.Date.Format("2006-01-02") {
-				dayMessages = append(dayMessages, msg)
-			}
+	if len(report.Sections) > 0 {
+		for _, section := range report.Sections {
+			fmt.Fprintf(w, "\n%s %s\n", r.label(section.Label), section.Title)
+			r.writeSection(w, section)
 		}
-
-		showModelBreakdown(dayMessages)
-	}
-
-	fmt.Println("\n" + strings.Repeat("═", 80))
-	ShowDaily(dailyUsage, ascending)
-	return nil
-}
-
-func ShowMonthly(monthlyUsage []models.MonthlyUsage, ascending bool) error {
-	if ascending {
-		sort.Slice(monthlyUsage, func(i, j int) bool {
-			if monthlyUsage[i].Year != monthlyUsage[j].Year {
-				return monthlyUsage[i].Year > monthlyUsage[j].Year
-			}
-			return monthlyUsage[i].Month > monthlyUsage[j].Month
-		})
+		fmt.Fprintln(w, "\n"+strings.Repeat("═", 80))
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Month", "Models", "Input", "Output", "Cache Create", "Cache Read", "Total", "Cost (USD)"})
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(r.Costs.columnNames(report.Columns))
 	table.SetBorder(true)
 	table.SetRowLine(true)
 	table.SetCenterSeparator("+")
 	table.SetColumnSeparator("│")
 	table.SetRowSeparator("─")
-	table.SetHeaderColor(
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-	)
-
-	var totalUsage models.TokenUsage
-	var totalCost float64
-
-	for _, monthly := range monthlyUsage {
-		modelNames := getShortModelNames(monthly.Models)
-		table.Append([]string{
-			fmt.Sprintf("%d-%02d", monthly.Year, monthly.Month),
-			strings.Join(modelNames, ", "),
-			formatNumber(monthly.TokenUsage.InputTokens),
-			formatNumber(monthly.TokenUsage.OutputTokens),
-			formatNumber(monthly.TokenUsage.CacheCreateTokens),
-			formatNumber(monthly.TokenUsage.CacheReadTokens),
-			formatNumber(monthly.TokenUsage.Total()),
-			fmt.Sprintf("$%.4f", monthly.CostUSD),
-		})
-
-		totalUsage.InputTokens += monthly.TokenUsage.InputTokens
-		totalUsage.OutputTokens += monthly.TokenUsage.OutputTokens
-		totalUsage.CacheCreateTokens += monthly.TokenUsage.CacheCreateTokens
-		totalUsage.CacheReadTokens += monthly.TokenUsage.CacheReadTokens
-		totalCost += monthly.CostUSD
+	if r.Color {
+		headerColors := make([]tablewriter.Colors, len(report.Columns))
+		for i := range headerColors {
+			headerColors[i] = tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold}
+		}
+		table.SetHeaderColor(headerColors...)
 	}
 
-	table.SetFooter([]string{
-		"TOTAL", "",
-		formatNumber(totalUsage.InputTokens),
-		formatNumber(totalUsage.OutputTokens),
-		formatNumber(totalUsage.CacheCreateTokens),
-		formatNumber(totalUsage.CacheReadTokens),
-		formatNumber(totalUsage.Total()),
-		fmt.Sprintf("$%.4f", totalCost),
-	})
-	table.SetFooterColor(
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-	)
+	for _, row := range report.Rows {
+		table.Append(r.Costs.cellTexts(row))
+	}
+
+	if report.Totals != nil {
+		footer := r.Costs.cellTexts(report.Totals)
+		table.SetFooter(footer)
+		if r.Color {
+			footerColors := make([]tablewriter.Colors, len(footer))
+			for i, text := range footer {
+				if text != "" {
+					footerColors[i] = tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold}
+				}
+			}
+			table.SetFooterColor(footerColors...)
+		}
+	}
 
 	table.Render()
+	r.writeNotes(w, report.Notes)
 	return nil
 }
 
-func ShowMonthlyWithBreakdown(monthlyUsage []models.MonthlyUsage, messages []models.Message, ascending bool) error {
-	if ascending {
-		sort.Slice(monthlyUsage, func(i, j int) bool {
-			if monthlyUsage[i].Year != monthlyUsage[j].Year {
-				return monthlyUsage[i].Year > monthlyUsage[j].Year
-			}
-			return monthlyUsage[i].Month > monthlyUsage[j].Month
-		})
+// writeSection writes a breakdown as a borderless, right-aligned table.
+func (r TableRenderer) writeSection(w io.Writer, section Section) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(r.Costs.columnNames(section.Columns))
+	table.SetBorder(false)
+	table.SetHeaderLine(false)
+	table.SetColumnSeparator(" ")
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+
+	for _, row := range section.Rows {
+		texts := r.Costs.cellTexts(row)
+		if r.Color {
+			texts[0] = modelColor.Sprint(texts[0])
+			texts[len(texts)-1] = costColor.Sprint(texts[len(texts)-1])
+		}
+		table.Append(texts)
 	}
 
-	for _, monthly := range monthlyUsage {
-		fmt.Printf("\n%s %d-%02d\n", headerColor.Sprint("Month:"), monthly.Year, monthly.Month)
+	table.Render()
+}
 
-		var monthMessages []models.Message
-		for _, msg := range messages {
-			if msg.Timestamp.Year() == monthly.Year && msg.Timestamp.Month() == monthly.Month {
-				monthMessages = append(monthMessages, msg)
-			}
+func (r TableRenderer) writeNotes(w io.Writer, notes []Note) {
+	for _, note := range notes {
+		if note.Label == "" {
+			fmt.Fprintln(w, r.Costs.noteText(note))
+			continue
 		}
-
-		showModelBreakdown(monthMessages)
+		fmt.Fprintf(w, "%s %s\n", r.label(note.Label), r.Costs.noteText(note))
 	}
+}
 
-	fmt.Println("\n" + strings.Repeat("═", 80))
-	ShowMonthly(monthlyUsage, ascending)
-	return nil
+func (r TableRenderer) label(s string) string {
+	if r.Color {
+		return headerColor.Sprint(s)
+	}
+	return s
 }
 
-func ShowSession(sessionUsage []models.SessionUsage, ascending bool) error {
-	if !ascending {
-		sort.Slice(sessionUsage, func(i, j int) bool {
-			return sessionUsage[i].StartTime.After(sessionUsage[j].StartTime)
-		})
+// columnName adds the currency to the names of cost columns.
+func (f CostFormat) columnName(column Column) string {
+	if column.Cost {
+		return column.Name + " (" + f.currency() + ")"
 	}
+	return column.Name
+}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Session ID", "Start Time", "Models", "Input", "Output", "Cache Create", "Cache Read", "Total", "Cost (USD)"})
-	table.SetBorder(true)
-	table.SetRowLine(true)
-	table.SetCenterSeparator("+")
-	table.SetColumnSeparator("│")
-	table.SetRowSeparator("─")
-	table.SetHeaderColor(
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgCyanColor, tablewriter.Bold},
-	)
-
-	var totalUsage models.TokenUsage
-	var totalCost float64
-
-	for _, session := range sessionUsage {
-		modelNames := getShortModelNames(session.Models)
-		table.Append([]string{
-			session.SessionID[:8] + "...",
-			session.StartTime.Format("2006-01-02 15:04"),
-			strings.Join(modelNames, ", "),
-			formatNumber(session.TokenUsage.InputTokens),
-			formatNumber(session.TokenUsage.OutputTokens),
-			formatNumber(session.TokenUsage.CacheCreateTokens),
-			formatNumber(session.TokenUsage.CacheReadTokens),
-			formatNumber(session.TokenUsage.Total()),
-			fmt.Sprintf("$%.4f", session.CostUSD),
-		})
-
-		totalUsage.InputTokens += session.TokenUsage.InputTokens
-		totalUsage.OutputTokens += session.TokenUsage.OutputTokens
-		totalUsage.CacheCreateTokens += session.TokenUsage.CacheCreateTokens
-		totalUsage.CacheReadTokens += session.TokenUsage.CacheReadTokens
-		totalCost += session.CostUSD
+func (f CostFormat) columnNames(columns []Column) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = f.columnName(c)
 	}
+	return names
+}
 
-	table.SetFooter([]string{
-		"TOTAL", "", "",
-		formatNumber(totalUsage.InputTokens),
-		formatNumber(totalUsage.OutputTokens),
-		formatNumber(totalUsage.CacheCreateTokens),
-		formatNumber(totalUsage.CacheReadTokens),
-		formatNumber(totalUsage.Total()),
-		fmt.Sprintf("$%.4f", totalCost),
-	})
-	table.SetFooterColor(
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{},
-		tablewriter.Colors{},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-		tablewriter.Colors{tablewriter.FgYellowColor, tablewriter.Bold},
-	)
+// cellText is the text of cell as shown in tables.
+func (f CostFormat) cellText(cell Cell) string {
+	cost, ok := cell.Value.(models.Money)
+	if !ok {
+		return cell.Text
+	}
+	if cell.Signed {
+		return f.formatSigned(cost)
+	}
+	return f.format(cost)
+}
 
-	table.Render()
-	return nil
+func (f CostFormat) cellTexts(row Row) []string {
+	texts := make([]string, len(row))
+	for i, cell := range row {
+		texts[i] = f.cellText(cell)
+	}
+	return texts
 }
 
-func ShowSessionWithBreakdown(sessionUsage []models.SessionUsage, messages []models.Message, ascending bool) error {
-	if !ascending {
-		sort.Slice(sessionUsage, func(i, j int) bool {
-			return sessionUsage[i].StartTime.After(sessionUsage[j].StartTime)
-		})
+// noteText fills the costs of note into its text.
+func (f CostFormat) noteText(note Note) string {
+	if len(note.Costs) == 0 {
+		return note.Text
+	}
+	costs := make([]interface{}, len(note.Costs))
+	for i, cost := range note.Costs {
+		costs[i] = f.format(cost)
 	}
+	return fmt.Sprintf(note.Text, costs...)
+}
 
-	for _, session := range sessionUsage {
-		fmt.Printf("\n%s %s\n", headerColor.Sprint("Session:"), session.SessionID[:16]+"...")
-		fmt.Printf("%s %s - %s\n", headerColor.Sprint("Time Range:"),
-			session.StartTime.Format("2006-01-02 15:04"),
-			session.EndTime.Format("15:04"))
+// ShowUnpricedWarning prints the models that have no price of their own to
+// stderr, so that it never mixes with JSON output. Models priced as another
+// snapshot are listed with the price they were given.
+func ShowUnpricedWarning(unpriced []models.UnpricedModel) {
+	if len(unpriced) == 0 {
+		return
+	}
 
-		var sessionMessages []models.Message
-		for _, msg := range messages {
-			if msg.SessionID == session.SessionID {
-				sessionMessages = append(sessionMessages, msg)
-			}
+	var missing, borrowed []models.UnpricedModel
+	for _, u := range unpriced {
+		if u.PricedAs == "" {
+			missing = append(missing, u)
+		} else {
+			borrowed = append(borrowed, u)
 		}
-
-		showModelBreakdown(sessionMessages)
 	}
 
-	fmt.Println("\n" + strings.Repeat("═", 80))
-	ShowSession(sessionUsage, ascending)
-	return nil
+	warnColor := color.New(color.FgYellow)
+	if len(missing) > 0 {
+		warnColor.Fprintf(os.Stderr, "\nWarning: no pricing found for %d model(s); their usage is counted as $0:\n", len(missing))
+		for _, u := range missing {
+			warnColor.Fprintf(os.Stderr, "  %s: %s tokens in %d messages\n", u.Model, formatNumber(u.TokenUsage.Total()), u.Messages)
+		}
+	}
+	if len(borrowed) > 0 {
+		warnColor.Fprintf(os.Stderr, "\nWarning: no pricing found for %d model(s); they are priced as another snapshot:\n", len(borrowed))
+		for _, u := range borrowed {
+			warnColor.Fprintf(os.Stderr, "  %s: priced as %s ($%.2f input, $%.2f output per 1M tokens), %s tokens in %d messages\n",
+				u.Model, u.PricedAs, u.Pricing.InputPer1M, u.Pricing.OutputPer1M, formatNumber(u.TokenUsage.Total()), u.Messages)
+		}
+	}
+	warnColor.Fprintln(os.Stderr, "Add them to a pricing file (see --pricing-file) to set their price.")
 }
 
-func showModelBreakdown(messages []models.Message) {
-	breakdown := calculator.AggregateByModel(messages)
-
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Model", "Input", "Output", "Cache Create", "Cache Read", "Total", "Cost (USD)"})
-	table.SetBorder(false)
-	table.SetHeaderLine(false)
-	table.SetColumnSeparator(" ")
-	table.SetAlignment(tablewriter.ALIGN_RIGHT)
-
-	for _, b := range breakdown {
-		table.Append([]string{
-			modelColor.Sprint(models.GetModelShortName(b.Model)),
-			formatNumber(b.TokenUsage.InputTokens),
-			formatNumber(b.TokenUsage.OutputTokens),
-			formatNumber(b.TokenUsage.CacheCreateTokens),
-			formatNumber(b.TokenUsage.CacheReadTokens),
-			formatNumber(b.TokenUsage.Total()),
-			costColor.Sprintf("$%.4f", b.CostUSD),
-		})
+// breakdownName labels a breakdown row with its model and, where they affect
+// the price, its price period, long context and service tier.
+func breakdownName(b models.ModelBreakdown) string {
+	name := models.GetModelShortName(b.Model)
+	if b.PricePeriod != "" {
+		name += " (" + b.PricePeriod + ")"
 	}
-
-	table.Render()
+	if b.LongContext {
+		name += " [long context]"
+	}
+	if b.ServiceTier != "" && b.ServiceTier != models.ServiceTierStandard {
+		name += " [" + b.ServiceTier + "]"
+	}
+	return name
 }
 
 func getShortModelNames(modelList []string) []string {
@@ -362,6 +248,55 @@ func getShortModelNames(modelList []string) []string {
 	return shortNames
 }
 
+// formatPercent shows the difference relative to the recorded cost.
+func formatPercent(audit models.CostAudit) string {
+	if audit.RecordedCost == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f%%", audit.Difference.Float64()/audit.RecordedCost.Float64()*100)
+}
+
+func formatProject(project string) string {
+	if project == "" {
+		return "(unknown)"
+	}
+	return project
+}
+
+func (f CostFormat) currency() string {
+	if f.Currency == "" {
+		return models.BaseCurrency
+	}
+	return f.Currency
+}
+
+func (f CostFormat) format(cost models.Money) string {
+	symbol, ok := currencySymbols[f.currency()]
+	if !ok {
+		symbol = f.currency() + " "
+	}
+	if cost < 0 {
+		return "-" + symbol + cost.Abs().Fixed(f.Decimals)
+	}
+	return symbol + cost.Fixed(f.Decimals)
+}
+
+func (f CostFormat) formatSigned(cost models.Money) string {
+	if cost < 0 {
+		return f.format(cost)
+	}
+	return "+" + f.format(cost)
+}
+
+func formatPrice(price float64) string {
+	return fmt.Sprintf("$%.2f", price)
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
 func formatNumber(n int) string {
 	if n == 0 {
 		return "-"
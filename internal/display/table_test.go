@@ -1,7 +1,10 @@
 package display
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
 )
 
 func TestFormatNumber(t *testing.T) {
@@ -130,3 +133,101 @@ func TestGetShortModelNames_PreservesOrderForUnique(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatCost(t *testing.T) {
+	tests := []struct {
+		decimals int
+		cost     models.Money
+		expected string
+	}{
+		{4, models.USD(15.11125), "$15.1113"},
+		{2, models.USD(15.11125), "$15.11"},
+		{4, models.USD(-0.125), "-$0.1250"},
+		{0, models.USD(0.4), "$0"},
+	}
+
+	for _, tt := range tests {
+		f := CostFormat{Decimals: tt.decimals}
+		if result := f.format(tt.cost); result != tt.expected {
+			t.Errorf("formatCost(%v) with %d decimals = %s, want %s", tt.cost, tt.decimals, result, tt.expected)
+		}
+	}
+}
+
+func TestFormatCost_Currency(t *testing.T) {
+	tests := []struct {
+		currency string
+		expected string
+		header   string
+	}{
+		{"", "$1.5000", "Cost (USD)"},
+		{"JPY", "¥1.5000", "Cost (JPY)"},
+		{"CHF", "CHF 1.5000", "Cost (CHF)"},
+	}
+
+	for _, tt := range tests {
+		f := CostFormat{Currency: tt.currency, Decimals: DefaultCostDecimals}
+		if result := f.format(models.USD(1.5)); result != tt.expected {
+			t.Errorf("format() in %q = %s, want %s", tt.currency, result, tt.expected)
+		}
+		if header := f.columnName(Column{Name: "Cost", Cost: true}); header != tt.header {
+			t.Errorf("columnName() in %q = %s, want %s", tt.currency, header, tt.header)
+		}
+	}
+}
+
+func TestTableRenderer(t *testing.T) {
+	expected := "" +
+		"+────────────+──────────────────+───────+────────+─────────────────+─────────────────+────────────+────────────+───────+────────────+\n" +
+		"│    DATE    │      MODELS      │ INPUT │ OUTPUT │ CACHE CREATE 5M │ CACHE CREATE 1H │ CACHE READ │ WEB SEARCH │ TOTAL │ COST (USD) │\n" +
+		"+────────────+──────────────────+───────+────────+─────────────────+─────────────────+────────────+────────────+───────+────────────+\n" +
+		"│ 2025-06-01 │ Sonnet 4, Opus 4 │  1010 │    100 │ -               │ -               │ -          │ -          │  1110 │ $0.7500    │\n" +
+		"+────────────+──────────────────+───────+────────+─────────────────+─────────────────+────────────+────────────+───────+────────────+\n" +
+		"│ 2025-06-02 │ Sonnet 4         │ -     │      5 │ -               │ -               │ -          │ -          │     5 │ $0.1250    │\n" +
+		"+────────────+──────────────────+───────+────────+─────────────────+─────────────────+────────────+────────────+───────+────────────+\n" +
+		"│   TOTAL    │                    1010  │  105   │        -        │        -        │     -      │     -      │ 1115  │  $0.8750   │\n" +
+		"+────────────+──────────────────+───────+────────+─────────────────+─────────────────+────────────+────────────+───────+────────────+\n"
+
+	if out := render(t, TableRenderer{Costs: testCosts}, testDailyReport(false)); out != expected {
+		t.Errorf("Render() =\n%s\nwant\n%s", out, expected)
+	}
+
+	breakdown := "" +
+		"\nDate: 2025-06-01\n" +
+		"   MODEL     INPUT   OUTPUT   CACHE CREATE 5M   CACHE CREATE 1H   CACHE READ   WEB SEARCH   TOTAL   COST (USD)  \n" +
+		"  Sonnet 4    1000      100                 -                 -            -            -    1100      $0.5000  \n" +
+		"    Opus 4      10        -                 -                 -            -            -      10      $0.2500  \n" +
+		"\nDate: 2025-06-02\n" +
+		"   MODEL     INPUT   OUTPUT   CACHE CREATE 5M   CACHE CREATE 1H   CACHE READ   WEB SEARCH   TOTAL   COST (USD)  \n" +
+		"  Sonnet 4       -        5                 -                 -            -            -       5      $0.1250  \n" +
+		"\n" + strings.Repeat("═", 80) + "\n"
+
+	if out := render(t, TableRenderer{Costs: testCosts}, testDailyReport(true)); out != breakdown+expected {
+		t.Errorf("Render() with breakdown =\n%s\nwant\n%s", out, breakdown+expected)
+	}
+}
+
+func TestTableRendererNotes(t *testing.T) {
+	report := Report{
+		Columns: []Column{{Name: "Model"}},
+		Intro:   []Note{{Label: "Plan:", Text: "Pro"}},
+		Notes:   []Note{{}, {Text: "done"}},
+		Empty:   "Nothing to show.",
+	}
+
+	if out := render(t, TableRenderer{Costs: testCosts}, report); out != "Nothing to show.\n" {
+		t.Errorf("Render() of empty report = %q", out)
+	}
+
+	report.Rows = []Row{{textCell("Opus 4")}}
+	expected := "Plan: Pro\n" +
+		"+────────+\n" +
+		"│ MODEL  │\n" +
+		"+────────+\n" +
+		"│ Opus 4 │\n" +
+		"+────────+\n" +
+		"\ndone\n"
+	if out := render(t, TableRenderer{Costs: testCosts}, report); out != expected {
+		t.Errorf("Render() =\n%q\nwant\n%q", out, expected)
+	}
+}
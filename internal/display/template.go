@@ -0,0 +1,105 @@
+package display
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// UsageTotals totals the rows of a usage report.
+type UsageTotals struct {
+	TokenUsage models.TokenUsage
+	Cost       models.Money
+}
+
+// TemplateData is what templates are executed on. Rows are the report's
+// values, such as []models.DailyUsage, in report order, and Totals their
+// totals, or nil if the report has none.
+type TemplateData struct {
+	Title    string
+	Rows     interface{}
+	Totals   interface{}
+	Currency string
+}
+
+// TemplateRenderer writes a report with a text/template. Costs is how the
+// cost helper writes costs.
+type TemplateRenderer struct {
+	Template *template.Template
+	Costs    CostFormat
+}
+
+// NewTemplateRenderer parses text as a template with the helpers of
+// templateFuncs.
+func NewTemplateRenderer(text string, costs CostFormat) (*TemplateRenderer, error) {
+	tmpl, err := template.New("report").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateRenderer{Template: tmpl, Costs: costs}, nil
+}
+
+func (r *TemplateRenderer) Render(w io.Writer, report Report) error {
+	tmpl, err := r.Template.Clone()
+	if err != nil {
+		return err
+	}
+	tmpl.Funcs(template.FuncMap{"cost": r.Costs.format})
+	var rows, totals interface{}
+	if report.Data != nil {
+		rows, totals = report.Data.templateData()
+	}
+	return tmpl.Execute(w, TemplateData{
+		Title:    report.Title,
+		Rows:     rows,
+		Totals:   totals,
+		Currency: r.Costs.currency(),
+	})
+}
+
+func (d DailyData) templateData() (interface{}, interface{})   { return d.Days, d.Totals }
+func (d WeeklyData) templateData() (interface{}, interface{})  { return d.Weeks, d.Totals }
+func (d MonthlyData) templateData() (interface{}, interface{}) { return d.Months, d.Totals }
+func (d SessionData) templateData() (interface{}, interface{}) { return d.Sessions, d.Totals }
+func (d ProjectData) templateData() (interface{}, interface{}) { return d.Projects, d.Totals }
+func (d ServiceTierData) templateData() (interface{}, interface{}) {
+	return d.Tiers, d.Totals
+}
+func (d BlocksData) templateData() (interface{}, interface{})  { return d.Blocks, d.Totals }
+func (d PricingData) templateData() (interface{}, interface{}) { return d.Entries, nil }
+func (d AuditData) templateData() (interface{}, interface{})   { return d.Audits, d.Totals }
+func (d PlanData) templateData() (interface{}, interface{}) {
+	return d.Comparison.Months, d.Comparison
+}
+
+var templateFuncs = template.FuncMap{
+	"shortModel": models.GetModelShortName,
+	"shortModels": func(modelList []string) string {
+		return strings.Join(getShortModelNames(modelList), ", ")
+	},
+	"number": groupDigits,
+	// cost is replaced with the renderer's CostFormat when executed
+	"cost":  CostFormat{}.format,
+	"float": models.Money.Float64,
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"join": strings.Join,
+}
+
+// groupDigits writes n with comma thousands separators.
+func groupDigits(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
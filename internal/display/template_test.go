@@ -0,0 +1,79 @@
+package display
+
+import (
+	"testing"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		costs    CostFormat
+		expected string
+	}{
+		{
+			name:     "rows",
+			template: `{{range .Rows}}{{date .Date}} {{printf "%.2f" .Cost}}{{"\n"}}{{end}}`,
+			expected: "2025-06-01 0.75\n2025-06-02 0.13\n",
+		},
+		{
+			name:     "helpers",
+			template: `{{range .Rows}}{{shortModels .Models}}: {{number .TokenUsage.InputTokens}} in, {{cost .Cost}}{{"\n"}}{{end}}`,
+			costs:    testCosts,
+			expected: "Sonnet 4, Opus 4: 1,010 in, $0.7500\nSonnet 4: 0 in, $0.1250\n",
+		},
+		{
+			name:     "currency",
+			template: `{{cost .Totals.Cost}} {{.Currency}}`,
+			costs:    CostFormat{Currency: "EUR", Decimals: 2},
+			expected: "€0.88 EUR",
+		},
+		{
+			name:     "totals",
+			template: `{{.Title}}: {{number .Totals.TokenUsage.Total}} tokens, {{.Totals.Cost}} {{.Currency}}`,
+			expected: "Daily Usage: 1,115 tokens, 0.875 USD",
+		},
+		{
+			name:     "short model",
+			template: `{{shortModel "claude-opus-4-20250514"}} {{float .Totals.Cost}}`,
+			expected: "Opus 4 0.875",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := NewTemplateRenderer(tt.template, tt.costs)
+			if err != nil {
+				t.Fatalf("NewTemplateRenderer() error = %v", err)
+			}
+			if result := render(t, renderer, testDailyReport(false)); result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplateRendererParseError(t *testing.T) {
+	if _, err := NewTemplateRenderer("{{range .Rows}}", testCosts); err == nil {
+		t.Error("NewTemplateRenderer() expected error for unclosed range")
+	}
+}
+
+func TestGroupDigits(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1234567, "1,234,567"},
+		{-12345, "-12,345"},
+	}
+
+	for _, tt := range tests {
+		if result := groupDigits(tt.n); result != tt.expected {
+			t.Errorf("groupDigits(%d) = %q, want %q", tt.n, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BaseCurrency is the currency prices are quoted in.
+const BaseCurrency = "USD"
+
+// RatesFile is the format of an exchange rate file, in JSON or YAML. Rates are
+// units of the currency per US dollar. Months listed under monthly convert at
+// their own rate and all other months at rate:
+//
+//	currencies:
+//	  JPY:
+//	    rate: 150
+//	    monthly:
+//	      2025-05: 144.8
+//	      2025-06: 144.5
+//	  EUR:
+//	    rate: 0.92
+type RatesFile struct {
+	Currencies map[string]ExchangeRate `json:"currencies" yaml:"currencies"`
+}
+
+type ExchangeRate struct {
+	Rate    float64            `json:"rate" yaml:"rate"`
+	Monthly map[string]float64 `json:"monthly,omitempty" yaml:"monthly,omitempty"`
+}
+
+// RateAt returns the rate for the month of t, in t's location.
+func (r ExchangeRate) RateAt(t time.Time) float64 {
+	if rate, ok := r.Monthly[t.Format("2006-01")]; ok {
+		return rate
+	}
+	return r.Rate
+}
+
+// Convert converts an amount in US dollars at the rate for the month of t.
+func (r ExchangeRate) Convert(usd Money, t time.Time) Money {
+	return usd.Mul(r.RateAt(t))
+}
+
+var ratesFileNames = []string{"rates.json", "rates.yaml", "rates.yml"}
+
+// FindRatesFile returns the first exchange rate file in dir, or "" if there
+// is none.
+func FindRatesFile(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	for _, name := range ratesFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadRatesFile reads an exchange rate file, choosing YAML for .yaml and .yml
+// files and JSON otherwise. Currency codes are upper-cased.
+func LoadRatesFile(path string) (*RatesFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file RatesFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rates file %s: %w", path, err)
+	}
+
+	if len(file.Currencies) == 0 {
+		return nil, fmt.Errorf("rates file %s defines no currencies", path)
+	}
+
+	currencies := make(map[string]ExchangeRate, len(file.Currencies))
+	for code, rate := range file.Currencies {
+		if rate.Rate <= 0 {
+			return nil, fmt.Errorf("invalid rates file %s: rate for %s must be positive", path, code)
+		}
+		for month, monthly := range rate.Monthly {
+			if _, err := time.Parse("2006-01", month); err != nil {
+				return nil, fmt.Errorf("invalid rates file %s: month %q for %s must be YYYY-MM", path, month, code)
+			}
+			if monthly <= 0 {
+				return nil, fmt.Errorf("invalid rates file %s: rate for %s in %s must be positive", path, code, month)
+			}
+		}
+		currencies[strings.ToUpper(code)] = rate
+	}
+	file.Currencies = currencies
+
+	return &file, nil
+}
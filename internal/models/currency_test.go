@@ -0,0 +1,87 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadRatesFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"rates.json": `{"currencies":{"jpy":{"rate":150,"monthly":{"2025-05":145}},"EUR":{"rate":0.9}}}`,
+		"rates.yaml": `currencies:
+  jpy:
+    rate: 150
+    monthly:
+      2025-05: 145
+  EUR:
+    rate: 0.9
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(tempDir, name)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := LoadRatesFile(path)
+			if err != nil {
+				t.Fatalf("LoadRatesFile() error = %v", err)
+			}
+
+			jpy, ok := file.Currencies["JPY"]
+			if !ok {
+				t.Fatal("Currency codes should be upper-cased")
+			}
+			if rate := jpy.RateAt(time.Date(2025, 5, 31, 23, 0, 0, 0, time.UTC)); rate != 145 {
+				t.Errorf("JPY rate in May = %v, want 145", rate)
+			}
+			if rate := jpy.RateAt(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)); rate != 150 {
+				t.Errorf("JPY rate in June = %v, want 150", rate)
+			}
+			if file.Currencies["EUR"].Rate != 0.9 {
+				t.Errorf("EUR rate = %v, want 0.9", file.Currencies["EUR"].Rate)
+			}
+		})
+	}
+}
+
+func TestLoadRatesFile_Invalid(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"syntax.json", `{"currencies":`},
+		{"empty.json", `{"currencies":{}}`},
+		{"missing_rate.json", `{"currencies":{"JPY":{"monthly":{"2025-05":145}}}}`},
+		{"negative.json", `{"currencies":{"JPY":{"rate":-1}}}`},
+		{"bad_month.json", `{"currencies":{"JPY":{"rate":150,"monthly":{"2025-5-1":145}}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tempDir, tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := LoadRatesFile(path); err == nil {
+				t.Error("LoadRatesFile() should return an error")
+			}
+		})
+	}
+}
+
+func TestExchangeRate_Convert(t *testing.T) {
+	rate := ExchangeRate{Rate: 150.5}
+	if converted := rate.Convert(USD(2), time.Now()); converted != USD(301) {
+		t.Errorf("Convert() = %v, want 301", converted)
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Date is a calendar day in UTC that reads and writes as "2006-01-02" in both
+// JSON and YAML.
+type Date struct {
+	time.Time
+}
+
+const dateLayout = "2006-01-02"
+
+func NewDate(year int, month time.Month, day int) *Date {
+	return &Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", s)
+	}
+	return Date{t}, nil
+}
+
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Date) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+func (d *Date) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseDate(node.Value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount in billionths of a currency unit, US dollars unless
+// converted. Prices are quoted per 1M tokens with at most three decimals, so
+// the cost of every single token is a whole number of nano-dollars and sums
+// never depend on order.
+type Money int64
+
+const (
+	moneyDecimals       = 9
+	Dollar        Money = 1_000_000_000
+)
+
+// USD converts a dollar amount, rounding to the nearest nano-dollar.
+func USD(dollars float64) Money {
+	return Money(math.Round(dollars * float64(Dollar)))
+}
+
+// PerToken converts a price per 1M tokens to the exact cost of one token.
+func PerToken(pricePer1M float64) Money {
+	return Money(math.Round(pricePer1M * float64(Dollar) / 1_000_000))
+}
+
+// Mul scales m by f, rounding to the nearest unit.
+func (m Money) Mul(f float64) Money {
+	return Money(math.Round(float64(m) * f))
+}
+
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / float64(Dollar)
+}
+
+// Fixed rounds m half away from zero to the given number of decimal places.
+func (m Money) Fixed(decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	if decimals > moneyDecimals {
+		decimals = moneyDecimals
+	}
+
+	sign := ""
+	abs := uint64(m)
+	if m < 0 {
+		sign = "-"
+		abs = uint64(-m)
+	}
+
+	unit := uint64(math.Pow10(moneyDecimals - decimals))
+	abs = (abs + unit/2) / unit
+	if abs == 0 {
+		sign = ""
+	}
+
+	scale := uint64(math.Pow10(decimals))
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, abs)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/scale, decimals, abs%scale)
+}
+
+// String is the exact amount without trailing zeros.
+func (m Money) String() string {
+	s := m.Fixed(moneyDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Format implements fmt.Formatter, so that printf and templates can treat m as
+// an amount: %f and %F round exactly like Fixed, %e and %g format Float64, %d
+// prints the nano-units, and other verbs print String.
+func (m Money) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'f', 'F':
+		prec, ok := f.Precision()
+		if !ok {
+			prec = 6
+		}
+		s := m.Fixed(prec)
+		if f.Flag('+') && m >= 0 {
+			s = "+" + s
+		}
+		fmt.Fprintf(f, formatDirective(f, 's', false), s)
+	case 'e', 'E', 'g', 'G':
+		fmt.Fprintf(f, formatDirective(f, verb, true), m.Float64())
+	case 'd':
+		fmt.Fprintf(f, formatDirective(f, verb, true), int64(m))
+	default:
+		fmt.Fprintf(f, formatDirective(f, 's', false), m.String())
+	}
+}
+
+// formatDirective rebuilds the directive f was called with for verb, keeping
+// the precision only if withPrecision is set.
+func formatDirective(f fmt.State, verb rune, withPrecision bool) string {
+	var sb strings.Builder
+	sb.WriteByte('%')
+	for _, flag := range "+- #0" {
+		if f.Flag(int(flag)) {
+			sb.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		sb.WriteString(strconv.Itoa(width))
+	}
+	if prec, ok := f.Precision(); ok && withPrecision {
+		sb.WriteString("." + strconv.Itoa(prec))
+	}
+	sb.WriteRune(verb)
+	return sb.String()
+}
+
+// MarshalJSON writes the exact amount as a JSON number.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number without going through float64, so that
+// amounts with up to nine decimals are kept exactly.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	money, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
+
+// ParseMoney parses a decimal dollar amount. Digits beyond nine decimal places
+// are rounded.
+func ParseMoney(s string) (Money, error) {
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+		}
+		return USD(f), nil
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	round := false
+	if len(frac) > moneyDecimals {
+		round = frac[moneyDecimals] >= '5'
+		frac = frac[:moneyDecimals]
+	}
+	frac += strings.Repeat("0", moneyDecimals-len(frac))
+
+	n, err := strconv.ParseUint(whole+frac, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	if round {
+		n++
+	}
+
+	if negative {
+		return -Money(n), nil
+	}
+	return Money(n), nil
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestMoney_Fixed(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		decimals int
+		expected string
+	}{
+		{"Four decimals", USD(15.11125), 4, "15.1113"},
+		{"Round half away from zero", USD(-0.00005), 4, "-0.0001"},
+		{"Rounds to zero without sign", USD(-0.00004), 4, "0.0000"},
+		{"Two decimals", USD(1234.5), 2, "1234.50"},
+		{"No decimals", USD(2.5), 0, "3"},
+		{"All decimals", Money(1), 9, "0.000000001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.money.Fixed(tt.decimals); result != tt.expected {
+				t.Errorf("Fixed(%d) = %s, want %s", tt.decimals, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	tests := []struct {
+		json  string
+		money Money
+	}{
+		{"0.375", 375_000_000},
+		{"110.25", 110_250_000_000},
+		{"0.000000003", 3},
+		{"-1.5", -1_500_000_000},
+		{"0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			data, err := json.Marshal(tt.money)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("Marshal() = %s, want %s", data, tt.json)
+			}
+
+			var money Money
+			if err := json.Unmarshal([]byte(tt.json), &money); err != nil {
+				t.Fatal(err)
+			}
+			if money != tt.money {
+				t.Errorf("Unmarshal() = %d, want %d", money, tt.money)
+			}
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Money
+		wantErr  bool
+	}{
+		{input: "0.1234567894", expected: 123456789},
+		{input: "0.1234567895", expected: 123456790},
+		{input: "1e-3", expected: 1_000_000},
+		{input: ".5", expected: 500_000_000},
+		{input: "", wantErr: true},
+		{input: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseMoney(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMoney() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseMoney() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPerToken(t *testing.T) {
+	// Every built-in price is a whole number of nano-dollars per token
+	for model, pricing := range ModelPricing {
+		for _, price := range []float64{pricing.InputPer1M, pricing.OutputPer1M, pricing.CacheCreatePer1M, pricing.CacheCreate1hRate(), pricing.CacheReadPer1M} {
+			if got := PerToken(price).Float64() * 1_000_000; got-price > 1e-12 || price-got > 1e-12 {
+				t.Errorf("Model %s: PerToken(%v) = %d is not exact", model, price, PerToken(price))
+			}
+		}
+	}
+}
+
+func TestMoney_Formatter(t *testing.T) {
+	tests := []struct {
+		format   string
+		money    Money
+		expected string
+	}{
+		{"%.2f", USD(0.125), "0.13"},
+		{"%.2f", USD(-0.125), "-0.13"},
+		{"%f", USD(1.5), "1.500000"},
+		{"%8.2f", USD(1.5), "    1.50"},
+		{"%+.1f", USD(2), "+2.0"},
+		{"%v", USD(0.0001), "0.0001"},
+		{"%s", USD(3), "3"},
+		{"%d", Money(42), "42"},
+		{"%.1e", USD(1500), "1.5e+03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if result := fmt.Sprintf(tt.format, tt.money); result != tt.expected {
+				t.Errorf("Sprintf(%q) = %q, want %q", tt.format, result, tt.expected)
+			}
+		})
+	}
+}
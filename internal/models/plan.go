@@ -0,0 +1,79 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// SubscriptionPlan is a flat-rate Claude subscription. UsageMultiplier is the
+// plan's usage allowance relative to Pro.
+type SubscriptionPlan struct {
+	Name            string
+	PriceUSD        Money
+	UsageMultiplier int
+}
+
+// SubscriptionPlans are the built-in plans, cheapest first.
+var SubscriptionPlans = []SubscriptionPlan{
+	{Name: "Pro", PriceUSD: USD(20), UsageMultiplier: 1},
+	{Name: "Max 5x", PriceUSD: USD(100), UsageMultiplier: 5},
+	{Name: "Max 20x", PriceUSD: USD(200), UsageMultiplier: 20},
+}
+
+// PayAsYouGo names the option of paying API prices instead of a plan.
+const PayAsYouGo = "API"
+
+// FindSubscriptionPlan looks up a plan by name, ignoring case, spaces and
+// dashes, so "max5x" and "Max 5x" both match.
+func FindSubscriptionPlan(name string) (SubscriptionPlan, bool) {
+	key := planKey(name)
+	for _, plan := range SubscriptionPlans {
+		if planKey(plan.Name) == key {
+			return plan, true
+		}
+	}
+	return SubscriptionPlan{}, false
+}
+
+func planKey(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(name))
+}
+
+// PlanMonth compares a month of API-equivalent usage with a plan. Costs and
+// prices are in the report currency.
+type PlanMonth struct {
+	Year  int
+	Month time.Month
+	// APICost is what the month's usage would have cost at API prices
+	APICost   Money
+	PlanPrice Money
+	// ValueMultiplier is APICost divided by PlanPrice
+	ValueMultiplier float64
+	Savings         Money
+	// BreakEven is the day the month's API-equivalent cost reached the plan
+	// price, or nil if it never did
+	BreakEven *time.Time `json:",omitempty"`
+	// PeakBlockCost is the API-equivalent cost of the busiest 5-hour block
+	PeakBlockCost Money
+	CheapestPlan  string
+	CheapestCost  Money
+}
+
+// PlanComparison compares usage with a plan month by month and over the
+// whole history. Costs and prices are in the report currency, except for the
+// list price of Plan.
+type PlanComparison struct {
+	Plan SubscriptionPlan
+	// PlanPrice is the monthly price of Plan in the report currency, at the
+	// exchange rate of the latest month with usage
+	PlanPrice       Money
+	Months          []PlanMonth
+	APICost         Money
+	PlanCost        Money
+	ValueMultiplier float64
+	Savings         Money
+	// CheapestPlan is the option, a plan or PayAsYouGo, that would have cost
+	// the least over all months
+	CheapestPlan string
+	CheapestCost Money
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestFindSubscriptionPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		found    bool
+	}{
+		{"pro", "Pro", true},
+		{"max5x", "Max 5x", true},
+		{"Max 20x", "Max 20x", true},
+		{"max-20x", "Max 20x", true},
+		{"team", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, ok := FindSubscriptionPlan(tt.name)
+			if ok != tt.found || plan.Name != tt.expected {
+				t.Errorf("FindSubscriptionPlan(%q) = %q, %v, want %q, %v", tt.name, plan.Name, ok, tt.expected, tt.found)
+			}
+		})
+	}
+}
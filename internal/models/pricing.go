@@ -1,61 +1,281 @@
 package models
 
-var ModelPricing = map[string]struct {
-	InputPer1M       float64
-	OutputPer1M      float64
-	CacheCreatePer1M float64
-	CacheReadPer1M   float64
-}{
+import (
+	"strings"
+	"time"
+)
+
+// Pricing is the price of a model in USD per 1M tokens. EffectiveFrom is
+// inclusive and EffectiveUntil exclusive; nil means unbounded.
+type Pricing struct {
+	InputPer1M       float64 `json:"input_per_1m" yaml:"input_per_1m"`
+	OutputPer1M      float64 `json:"output_per_1m" yaml:"output_per_1m"`
+	CacheCreatePer1M float64 `json:"cache_create_per_1m" yaml:"cache_create_per_1m"`
+	// CacheCreate1hPer1M defaults to twice the input price when zero
+	CacheCreate1hPer1M float64 `json:"cache_create_1h_per_1m,omitempty" yaml:"cache_create_1h_per_1m,omitempty"`
+	CacheReadPer1M     float64 `json:"cache_read_per_1m" yaml:"cache_read_per_1m"`
+	// WebSearchPer1K defaults to DefaultWebSearchPer1K when nil; zero makes
+	// web searches free
+	WebSearchPer1K *float64 `json:"web_search_per_1k,omitempty" yaml:"web_search_per_1k,omitempty"`
+	EffectiveFrom  *Date    `json:"effective_from,omitempty" yaml:"effective_from,omitempty"`
+	EffectiveUntil *Date    `json:"effective_until,omitempty" yaml:"effective_until,omitempty"`
+	// LongContext replaces the rates above for requests whose prompt exceeds
+	// its threshold
+	LongContext *LongContextPricing `json:"long_context,omitempty" yaml:"long_context,omitempty"`
+}
+
+// DefaultLongContextThreshold is the prompt size above which long context
+// rates apply when a pricing entry does not set its own threshold.
+const DefaultLongContextThreshold = 200_000
+
+// LongContextPricing is the premium price tier for requests with a prompt of
+// more than Threshold tokens, counting input and cache tokens.
+type LongContextPricing struct {
+	Threshold          int     `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	InputPer1M         float64 `json:"input_per_1m" yaml:"input_per_1m"`
+	OutputPer1M        float64 `json:"output_per_1m" yaml:"output_per_1m"`
+	CacheCreatePer1M   float64 `json:"cache_create_per_1m" yaml:"cache_create_per_1m"`
+	CacheCreate1hPer1M float64 `json:"cache_create_1h_per_1m,omitempty" yaml:"cache_create_1h_per_1m,omitempty"`
+	CacheReadPer1M     float64 `json:"cache_read_per_1m" yaml:"cache_read_per_1m"`
+}
+
+func (l LongContextPricing) ThresholdTokens() int {
+	if l.Threshold > 0 {
+		return l.Threshold
+	}
+	return DefaultLongContextThreshold
+}
+
+// IsLongContext reports whether a single request with usage is billed at the
+// long context rates.
+func (p Pricing) IsLongContext(usage TokenUsage) bool {
+	return p.LongContext != nil && usage.PromptTokens() > p.LongContext.ThresholdTokens()
+}
+
+// ForRequest returns the rates that apply to a single request with usage.
+func (p Pricing) ForRequest(usage TokenUsage) Pricing {
+	if !p.IsLongContext(usage) {
+		return p
+	}
+	return p.LongContextRates()
+}
+
+// LongContextRates returns the long context tier as a Pricing for the same
+// period. It must only be called when LongContext is set.
+func (p Pricing) LongContextRates() Pricing {
+	return Pricing{
+		InputPer1M:         p.LongContext.InputPer1M,
+		OutputPer1M:        p.LongContext.OutputPer1M,
+		CacheCreatePer1M:   p.LongContext.CacheCreatePer1M,
+		CacheCreate1hPer1M: p.LongContext.CacheCreate1hPer1M,
+		CacheReadPer1M:     p.LongContext.CacheReadPer1M,
+		WebSearchPer1K:     p.WebSearchPer1K,
+		EffectiveFrom:      p.EffectiveFrom,
+		EffectiveUntil:     p.EffectiveUntil,
+	}
+}
+
+// CacheCreate1hRate is the price of 1-hour cache writes.
+func (p Pricing) CacheCreate1hRate() float64 {
+	if p.CacheCreate1hPer1M > 0 {
+		return p.CacheCreate1hPer1M
+	}
+	return 2 * p.InputPer1M
+}
+
+// DefaultWebSearchPer1K is the price in USD of 1,000 web searches.
+const DefaultWebSearchPer1K = 10.00
+
+// WebSearchRate is the price of 1,000 web searches.
+func (p Pricing) WebSearchRate() float64 {
+	if p.WebSearchPer1K != nil {
+		return *p.WebSearchPer1K
+	}
+	return DefaultWebSearchPer1K
+}
+
+func (p Pricing) Covers(t time.Time) bool {
+	if p.EffectiveFrom != nil && t.Before(p.EffectiveFrom.Time) {
+		return false
+	}
+	if p.EffectiveUntil != nil && !t.Before(p.EffectiveUntil.Time) {
+		return false
+	}
+	return true
+}
+
+// Period describes the dates a price applies to, or "" for an undated price.
+func (p Pricing) Period() string {
+	switch {
+	case p.EffectiveFrom != nil && p.EffectiveUntil != nil:
+		return p.EffectiveFrom.String() + " to " + p.EffectiveUntil.String()
+	case p.EffectiveFrom != nil:
+		return "from " + p.EffectiveFrom.String()
+	case p.EffectiveUntil != nil:
+		return "until " + p.EffectiveUntil.String()
+	}
+	return ""
+}
+
+// ModelPricing holds the current price of each model. Earlier prices live in
+// PricingHistory.
+var ModelPricing = map[string]Pricing{
 	"claude-opus-4-20250514": {
-		InputPer1M:       15.00,
-		OutputPer1M:      75.00,
-		CacheCreatePer1M: 18.75,
-		CacheReadPer1M:   1.50,
+		InputPer1M:         15.00,
+		OutputPer1M:        75.00,
+		CacheCreatePer1M:   18.75,
+		CacheCreate1hPer1M: 30.00,
+		CacheReadPer1M:     1.50,
 	},
 	"claude-sonnet-4-20250514": {
-		InputPer1M:       3.00,
-		OutputPer1M:      15.00,
-		CacheCreatePer1M: 3.75,
-		CacheReadPer1M:   0.30,
+		InputPer1M:         3.00,
+		OutputPer1M:        15.00,
+		CacheCreatePer1M:   3.75,
+		CacheCreate1hPer1M: 6.00,
+		CacheReadPer1M:     0.30,
+		// 1M context window requests with a prompt over 200K tokens
+		LongContext: &LongContextPricing{
+			InputPer1M:         6.00,
+			OutputPer1M:        22.50,
+			CacheCreatePer1M:   7.50,
+			CacheCreate1hPer1M: 12.00,
+			CacheReadPer1M:     0.60,
+		},
 	},
 	"claude-3-5-sonnet-20241022": {
-		InputPer1M:       3.00,
-		OutputPer1M:      15.00,
-		CacheCreatePer1M: 3.75,
-		CacheReadPer1M:   0.30,
+		InputPer1M:         3.00,
+		OutputPer1M:        15.00,
+		CacheCreatePer1M:   3.75,
+		CacheCreate1hPer1M: 6.00,
+		CacheReadPer1M:     0.30,
 	},
 	"claude-3-5-sonnet-20240620": {
-		InputPer1M:       3.00,
-		OutputPer1M:      15.00,
-		CacheCreatePer1M: 3.75,
-		CacheReadPer1M:   0.30,
+		InputPer1M:         3.00,
+		OutputPer1M:        15.00,
+		CacheCreatePer1M:   3.75,
+		CacheCreate1hPer1M: 6.00,
+		CacheReadPer1M:     0.30,
 	},
 	"claude-3-5-haiku-20241022": {
-		InputPer1M:       0.80,
-		OutputPer1M:      4.00,
-		CacheCreatePer1M: 1.00,
-		CacheReadPer1M:   0.08,
+		InputPer1M:         0.80,
+		OutputPer1M:        4.00,
+		CacheCreatePer1M:   1.00,
+		CacheCreate1hPer1M: 1.60,
+		CacheReadPer1M:     0.08,
+		EffectiveFrom:      NewDate(2024, time.December, 3),
 	},
 	"claude-3-opus-20240229": {
-		InputPer1M:       15.00,
-		OutputPer1M:      75.00,
-		CacheCreatePer1M: 18.75,
-		CacheReadPer1M:   1.50,
+		InputPer1M:         15.00,
+		OutputPer1M:        75.00,
+		CacheCreatePer1M:   18.75,
+		CacheCreate1hPer1M: 30.00,
+		CacheReadPer1M:     1.50,
 	},
 	"claude-3-sonnet-20240229": {
-		InputPer1M:       3.00,
-		OutputPer1M:      15.00,
-		CacheCreatePer1M: 3.75,
-		CacheReadPer1M:   0.30,
+		InputPer1M:         3.00,
+		OutputPer1M:        15.00,
+		CacheCreatePer1M:   3.75,
+		CacheCreate1hPer1M: 6.00,
+		CacheReadPer1M:     0.30,
 	},
 	"claude-3-haiku-20240307": {
-		InputPer1M:       0.25,
-		OutputPer1M:      1.25,
-		CacheCreatePer1M: 0.30,
-		CacheReadPer1M:   0.03,
+		InputPer1M:         0.25,
+		OutputPer1M:        1.25,
+		CacheCreatePer1M:   0.30,
+		CacheCreate1hPer1M: 0.50,
+		CacheReadPer1M:     0.03,
+	},
+}
+
+// PricingHistory holds superseded prices per model, each with an
+// EffectiveUntil date.
+var PricingHistory = map[string][]Pricing{
+	// Claude 3.5 Haiku launched at $1/$5 and was reduced to $0.80/$4
+	"claude-3-5-haiku-20241022": {
+		{
+			InputPer1M:         1.00,
+			OutputPer1M:        5.00,
+			CacheCreatePer1M:   1.25,
+			CacheCreate1hPer1M: 2.00,
+			CacheReadPer1M:     0.10,
+			EffectiveFrom:      NewDate(2024, time.November, 4),
+			EffectiveUntil:     NewDate(2024, time.December, 3),
+		},
 	},
 }
 
+// syntheticPricing prices synthetic messages, web searches included, at zero.
+var syntheticPricing = Pricing{WebSearchPer1K: new(float64)}
+
+// GetPricing returns the price of model in effect at t, resolving unknown
+// model IDs with ResolveModel. When no dated price covers t, the current
+// price is used. Synthetic messages are free.
+func GetPricing(model string, t time.Time) (Pricing, bool) {
+	if model == SyntheticModel {
+		return syntheticPricing, true
+	}
+
+	model, ok := ResolveModel(model)
+	if !ok {
+		return Pricing{}, false
+	}
+
+	for _, pricing := range PricingHistory[model] {
+		if pricing.Covers(t) {
+			return pricing, true
+		}
+	}
+	pricing, ok := ModelPricing[model]
+	return pricing, ok
+}
+
+// GetCurrentPricing returns the current price of model, resolving unknown
+// model IDs with ResolveModel.
+func GetCurrentPricing(model string) (Pricing, bool) {
+	if model == SyntheticModel {
+		return syntheticPricing, true
+	}
+
+	model, ok := ResolveModel(model)
+	if !ok {
+		return Pricing{}, false
+	}
+	pricing, ok := ModelPricing[model]
+	return pricing, ok
+}
+
+const (
+	ServiceTierStandard = "standard"
+	ServiceTierBatch    = "batch"
+	ServiceTierPriority = "priority"
+)
+
+// ServiceTierMultipliers scale the calculated cost of requests by their
+// service tier. Batch requests are billed at half price.
+var ServiceTierMultipliers = map[string]float64{
+	ServiceTierStandard: 1,
+	ServiceTierBatch:    0.5,
+	ServiceTierPriority: 1,
+}
+
+// NormalizeServiceTier lower-cases tier and maps a missing tier to standard.
+func NormalizeServiceTier(tier string) string {
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	if tier == "" {
+		return ServiceTierStandard
+	}
+	return tier
+}
+
+// ServiceTierMultiplier returns the cost multiplier of tier, 1 for unknown
+// tiers.
+func ServiceTierMultiplier(tier string) float64 {
+	if multiplier, ok := ServiceTierMultipliers[NormalizeServiceTier(tier)]; ok {
+		return multiplier
+	}
+	return 1
+}
+
 func GetModelShortName(model string) string {
 	shortNames := map[string]string{
 		"claude-opus-4-20250514":     "Opus 4",
@@ -70,5 +290,8 @@ func GetModelShortName(model string) string {
 	if short, ok := shortNames[model]; ok {
 		return short
 	}
+	if short, ok := shortNames[NormalizeModel(model)]; ok {
+		return short
+	}
 	return model
 }
@@ -0,0 +1,274 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const BuiltinPricingSource = "built-in"
+
+// PricingSources records where each entry of ModelPricing came from: either
+// BuiltinPricingSource or the path of the pricing file that set it.
+var PricingSources = map[string]string{}
+
+// PricingFile is the format of a user pricing file, in JSON or YAML. Each
+// model maps to a single price or to a list of dated prices:
+//
+//	models:
+//	  claude-opus-4-1-20250805:
+//	    input_per_1m: 15
+//	    output_per_1m: 75
+//	    cache_create_per_1m: 18.75
+//	    cache_read_per_1m: 1.5
+//	  claude-3-5-haiku-20241022:
+//	    - input_per_1m: 1
+//	      output_per_1m: 5
+//	      effective_until: 2024-12-03
+//	    - input_per_1m: 0.8
+//	      output_per_1m: 4
+//	      effective_from: 2024-12-03
+//	service_tiers:
+//	  batch: 0.5
+type PricingFile struct {
+	Models map[string]PricingPeriods `json:"models" yaml:"models"`
+	// ServiceTiers overrides the cost multiplier of service tiers
+	ServiceTiers map[string]float64 `json:"service_tiers,omitempty" yaml:"service_tiers,omitempty"`
+}
+
+// PricingPeriods is the price history of one model in a pricing file.
+type PricingPeriods []Pricing
+
+func (p *PricingPeriods) UnmarshalJSON(data []byte) error {
+	var single Pricing
+	if err := json.Unmarshal(data, &single); err == nil {
+		*p = PricingPeriods{single}
+		return nil
+	}
+	var list []Pricing
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*p = list
+	return nil
+}
+
+func (p *PricingPeriods) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var list []Pricing
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		*p = list
+		return nil
+	}
+	var single Pricing
+	if err := node.Decode(&single); err != nil {
+		return err
+	}
+	*p = PricingPeriods{single}
+	return nil
+}
+
+type PricingEntry struct {
+	Model   string
+	Pricing Pricing
+	Source  string
+}
+
+var pricingFileNames = []string{"pricing.json", "pricing.yaml", "pricing.yml"}
+
+// GetPricingConfigDir returns the directory searched for a pricing file when
+// none is given explicitly.
+func GetPricingConfigDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "claude-usage-go")
+}
+
+// FindPricingFile returns the first pricing file in dir, or "" if there is none.
+func FindPricingFile(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	for _, name := range pricingFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadPricingFile reads a pricing file, choosing YAML for .yaml and .yml
+// files and JSON otherwise.
+func LoadPricingFile(path string) (*PricingFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file PricingFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid pricing file %s: %w", path, err)
+	}
+
+	if len(file.Models) == 0 && len(file.ServiceTiers) == 0 {
+		return nil, fmt.Errorf("pricing file %s defines no models", path)
+	}
+	for tier, multiplier := range file.ServiceTiers {
+		if multiplier < 0 {
+			return nil, fmt.Errorf("invalid pricing file %s: negative multiplier for service tier %s", path, tier)
+		}
+	}
+	for model, periods := range file.Models {
+		if len(periods) == 0 {
+			return nil, fmt.Errorf("invalid pricing file %s: no prices for %s", path, model)
+		}
+		for _, pricing := range periods {
+			if hasNegativePrice(pricing) {
+				return nil, fmt.Errorf("invalid pricing file %s: negative price for %s", path, model)
+			}
+			if pricing.EffectiveFrom != nil && pricing.EffectiveUntil != nil && !pricing.EffectiveFrom.Before(pricing.EffectiveUntil.Time) {
+				return nil, fmt.Errorf("invalid pricing file %s: effective_from must be before effective_until for %s", path, model)
+			}
+		}
+		if err := checkPeriods(periods); err != nil {
+			return nil, fmt.Errorf("invalid pricing file %s: %v for %s", path, err, model)
+		}
+	}
+
+	return &file, nil
+}
+
+// checkPeriods rejects price histories that GetPricing could not resolve to a
+// single price: more than one open-ended price, or overlapping dates.
+func checkPeriods(periods PricingPeriods) error {
+	openEnded := 0
+	for _, pricing := range periods {
+		if pricing.EffectiveUntil == nil {
+			openEnded++
+		}
+	}
+	if openEnded > 1 {
+		return fmt.Errorf("more than one price without effective_until")
+	}
+
+	for i, pricing := range periods {
+		for _, other := range periods[:i] {
+			if overlaps(pricing, other) {
+				return fmt.Errorf("overlapping prices %s and %s", periodName(other), periodName(pricing))
+			}
+		}
+	}
+	return nil
+}
+
+// overlaps reports whether two prices apply to a common time. A missing
+// effective_from or effective_until leaves that side of the period open.
+func overlaps(a, b Pricing) bool {
+	if a.EffectiveUntil != nil && b.EffectiveFrom != nil && !b.EffectiveFrom.Before(a.EffectiveUntil.Time) {
+		return false
+	}
+	if b.EffectiveUntil != nil && a.EffectiveFrom != nil && !a.EffectiveFrom.Before(b.EffectiveUntil.Time) {
+		return false
+	}
+	return true
+}
+
+func periodName(p Pricing) string {
+	if period := p.Period(); period != "" {
+		return "(" + period + ")"
+	}
+	return "(undated)"
+}
+
+func hasNegativePrice(p Pricing) bool {
+	if p.InputPer1M < 0 || p.OutputPer1M < 0 || p.CacheCreatePer1M < 0 || p.CacheCreate1hPer1M < 0 || p.CacheReadPer1M < 0 || (p.WebSearchPer1K != nil && *p.WebSearchPer1K < 0) {
+		return true
+	}
+	if l := p.LongContext; l != nil {
+		return l.Threshold < 0 || l.InputPer1M < 0 || l.OutputPer1M < 0 || l.CacheCreatePer1M < 0 || l.CacheCreate1hPer1M < 0 || l.CacheReadPer1M < 0
+	}
+	return false
+}
+
+// ApplyPricingFile merges the models of a pricing file over ModelPricing and
+// PricingHistory. A model listed in the file replaces the built-in entry and
+// its history as a whole: prices with an effective_until date become its
+// history and the open-ended one becomes its current price.
+func ApplyPricingFile(file *PricingFile, source string) {
+	for model, periods := range file.Models {
+		delete(ModelPricing, model)
+		delete(PricingHistory, model)
+
+		for _, pricing := range periods {
+			if pricing.EffectiveUntil != nil {
+				PricingHistory[model] = append(PricingHistory[model], pricing)
+			} else {
+				ModelPricing[model] = pricing
+			}
+		}
+		PricingSources[model] = source
+	}
+	for tier, multiplier := range file.ServiceTiers {
+		ServiceTierMultipliers[NormalizeServiceTier(tier)] = multiplier
+	}
+	resetResolveCache()
+}
+
+// EffectivePricing lists the prices currently in use, including superseded
+// ones, sorted by model and then by date.
+func EffectivePricing() []PricingEntry {
+	var entries []PricingEntry
+	add := func(model string, pricing Pricing) {
+		source, ok := PricingSources[model]
+		if !ok {
+			source = BuiltinPricingSource
+		}
+		entries = append(entries, PricingEntry{
+			Model:   model,
+			Pricing: pricing,
+			Source:  source,
+		})
+	}
+
+	for model, pricing := range ModelPricing {
+		add(model, pricing)
+	}
+	for model, history := range PricingHistory {
+		for _, pricing := range history {
+			add(model, pricing)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Model != entries[j].Model {
+			return entries[i].Model < entries[j].Model
+		}
+		return startOf(entries[i].Pricing).Before(startOf(entries[j].Pricing))
+	})
+
+	return entries
+}
+
+func startOf(p Pricing) time.Time {
+	if p.EffectiveFrom == nil {
+		return time.Time{}
+	}
+	return p.EffectiveFrom.Time
+}
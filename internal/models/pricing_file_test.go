@@ -0,0 +1,232 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadPricingFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"pricing.json": `{"models":{"claude-new-model":{"input_per_1m":2,"output_per_1m":10,"cache_create_per_1m":2.5,"cache_read_per_1m":0.2}}}`,
+		"pricing.yaml": `models:
+  claude-new-model:
+    input_per_1m: 2
+    output_per_1m: 10
+    cache_create_per_1m: 2.5
+    cache_read_per_1m: 0.2
+`,
+	}
+
+	expected := Pricing{InputPer1M: 2, OutputPer1M: 10, CacheCreatePer1M: 2.5, CacheReadPer1M: 0.2}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(tempDir, name)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := LoadPricingFile(path)
+			if err != nil {
+				t.Fatalf("LoadPricingFile() error = %v", err)
+			}
+			periods := file.Models["claude-new-model"]
+			if len(periods) != 1 || periods[0] != expected {
+				t.Errorf("Pricing = %+v, want [%+v]", periods, expected)
+			}
+		})
+	}
+}
+
+func TestLoadPricingFile_WebSearch(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected float64
+	}{
+		{"default.json", `{"models":{"m":{"input_per_1m":1}}}`, DefaultWebSearchPer1K},
+		{"custom.json", `{"models":{"m":{"input_per_1m":1,"web_search_per_1k":5}}}`, 5},
+		{"free.json", `{"models":{"m":{"input_per_1m":1,"web_search_per_1k":0}}}`, 0},
+		{"free.yaml", "models:\n  m:\n    input_per_1m: 1\n    web_search_per_1k: 0\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tempDir, tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := LoadPricingFile(path)
+			if err != nil {
+				t.Fatalf("LoadPricingFile() error = %v", err)
+			}
+			if rate := file.Models["m"][0].WebSearchRate(); rate != tt.expected {
+				t.Errorf("WebSearchRate() = %f, want %f", rate, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadPricingFile_Invalid(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"syntax.json", `{"models":`},
+		{"empty.json", `{"models":{}}`},
+		{"negative.json", `{"models":{"m":{"input_per_1m":-1}}}`},
+		{"negative_tier.json", `{"service_tiers":{"batch":-0.5}}`},
+		{"negative_web_search.json", `{"models":{"m":{"input_per_1m":1,"web_search_per_1k":-1}}}`},
+		{"negative_long_context.json", `{"models":{"m":{"input_per_1m":1,"long_context":{"input_per_1m":-1}}}}`},
+		{"two_open_ended.json", `{"models":{"m":[{"input_per_1m":1},{"input_per_1m":2,"effective_from":"2025-03-01"}]}}`},
+		{"overlapping.json", `{"models":{"m":[
+			{"input_per_1m":1,"effective_from":"2025-01-01","effective_until":"2025-03-01"},
+			{"input_per_1m":2,"effective_from":"2025-02-01","effective_until":"2025-04-01"},
+			{"input_per_1m":3,"effective_from":"2025-04-01"}
+		]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tempDir, tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := LoadPricingFile(path); err == nil {
+				t.Error("LoadPricingFile() should return an error")
+			}
+		})
+	}
+}
+
+func TestApplyPricingFile_ServiceTiers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricing.yaml")
+	content := `service_tiers:
+  priority: 1.25
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := LoadPricingFile(path)
+	if err != nil {
+		t.Fatalf("LoadPricingFile() error = %v", err)
+	}
+
+	original := ServiceTierMultipliers[ServiceTierPriority]
+	defer func() { ServiceTierMultipliers[ServiceTierPriority] = original }()
+
+	ApplyPricingFile(file, path)
+	if multiplier := ServiceTierMultiplier("priority"); multiplier != 1.25 {
+		t.Errorf("Priority multiplier = %v, want 1.25", multiplier)
+	}
+	if multiplier := ServiceTierMultiplier("batch"); multiplier != 0.5 {
+		t.Errorf("Batch multiplier = %v, want 0.5", multiplier)
+	}
+}
+
+func TestFindPricingFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if path := FindPricingFile(tempDir); path != "" {
+		t.Errorf("FindPricingFile() = %s, want empty for a directory without pricing file", path)
+	}
+
+	yamlPath := filepath.Join(tempDir, "pricing.yaml")
+	if err := os.WriteFile(yamlPath, []byte("models: {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if path := FindPricingFile(tempDir); path != yamlPath {
+		t.Errorf("FindPricingFile() = %s, want %s", path, yamlPath)
+	}
+}
+
+func TestApplyPricingFile(t *testing.T) {
+	original := ModelPricing["claude-sonnet-4-20250514"]
+	defer func() {
+		ModelPricing["claude-sonnet-4-20250514"] = original
+		delete(ModelPricing, "claude-new-model")
+		PricingSources = map[string]string{}
+	}()
+
+	ApplyPricingFile(&PricingFile{Models: map[string]PricingPeriods{
+		"claude-sonnet-4-20250514": {{InputPer1M: 4, OutputPer1M: 16}},
+		"claude-new-model":         {{InputPer1M: 1, OutputPer1M: 5}},
+	}}, "/tmp/pricing.json")
+
+	if ModelPricing["claude-sonnet-4-20250514"].InputPer1M != 4 {
+		t.Errorf("Overridden input price = %f, want 4", ModelPricing["claude-sonnet-4-20250514"].InputPer1M)
+	}
+
+	sources := make(map[string]string)
+	for _, entry := range EffectivePricing() {
+		sources[entry.Model] = entry.Source
+	}
+
+	if sources["claude-sonnet-4-20250514"] != "/tmp/pricing.json" {
+		t.Errorf("Overridden model source = %s, want /tmp/pricing.json", sources["claude-sonnet-4-20250514"])
+	}
+	if sources["claude-new-model"] != "/tmp/pricing.json" {
+		t.Errorf("New model source = %s, want /tmp/pricing.json", sources["claude-new-model"])
+	}
+	if sources["claude-opus-4-20250514"] != BuiltinPricingSource {
+		t.Errorf("Built-in model source = %s, want %s", sources["claude-opus-4-20250514"], BuiltinPricingSource)
+	}
+}
+
+func TestLoadPricingFile_History(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"history.json": `{"models":{"claude-new-model":[
+			{"input_per_1m":2,"output_per_1m":10,"effective_until":"2025-03-01"},
+			{"input_per_1m":1,"output_per_1m":5,"effective_from":"2025-03-01"}
+		]}}`,
+		"history.yaml": `models:
+  claude-new-model:
+    - input_per_1m: 2
+      output_per_1m: 10
+      effective_until: 2025-03-01
+    - input_per_1m: 1
+      output_per_1m: 5
+      effective_from: 2025-03-01
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				delete(ModelPricing, "claude-new-model")
+				delete(PricingHistory, "claude-new-model")
+				PricingSources = map[string]string{}
+			}()
+
+			path := filepath.Join(tempDir, name)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := LoadPricingFile(path)
+			if err != nil {
+				t.Fatalf("LoadPricingFile() error = %v", err)
+			}
+			ApplyPricingFile(file, path)
+
+			before, _ := GetPricing("claude-new-model", time.Date(2025, 2, 28, 23, 0, 0, 0, time.UTC))
+			after, _ := GetPricing("claude-new-model", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+			if before.InputPer1M != 2 || after.InputPer1M != 1 {
+				t.Errorf("Input price before/after = %f/%f, want 2/1", before.InputPer1M, after.InputPer1M)
+			}
+		})
+	}
+}
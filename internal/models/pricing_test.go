@@ -2,6 +2,7 @@ package models
 
 import (
 	"testing"
+	"time"
 )
 
 func TestModelPricing(t *testing.T) {
@@ -126,3 +127,156 @@ func TestGetModelShortName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPricing_History(t *testing.T) {
+	model := "claude-3-5-haiku-20241022"
+
+	tests := []struct {
+		name          string
+		at            time.Time
+		expectedInput float64
+		expectedLabel string
+	}{
+		{
+			name:          "Launch price",
+			at:            time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC),
+			expectedInput: 1.00,
+			expectedLabel: "2024-11-04 to 2024-12-03",
+		},
+		{
+			name:          "Reduced price on the effective date",
+			at:            time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC),
+			expectedInput: 0.80,
+			expectedLabel: "from 2024-12-03",
+		},
+		{
+			name:          "Current price",
+			at:            time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedInput: 0.80,
+			expectedLabel: "from 2024-12-03",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricing, ok := GetPricing(model, tt.at)
+			if !ok {
+				t.Fatalf("GetPricing(%s) not found", model)
+			}
+			if pricing.InputPer1M != tt.expectedInput {
+				t.Errorf("Input price = %f, want %f", pricing.InputPer1M, tt.expectedInput)
+			}
+			if pricing.Period() != tt.expectedLabel {
+				t.Errorf("Period() = %q, want %q", pricing.Period(), tt.expectedLabel)
+			}
+		})
+	}
+}
+
+func TestGetPricing_Undated(t *testing.T) {
+	pricing, ok := GetPricing("claude-opus-4-20250514", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	if !ok {
+		t.Fatal("GetPricing() should fall back to the current price")
+	}
+	if pricing.InputPer1M != 15.00 || pricing.Period() != "" {
+		t.Errorf("Pricing = %+v (period %q), want undated $15 input", pricing, pricing.Period())
+	}
+
+	if _, ok := GetPricing("unknown-model", time.Now()); ok {
+		t.Error("GetPricing() should not find an unknown model")
+	}
+}
+
+func TestPricing_CacheCreate1hRate(t *testing.T) {
+	for model, pricing := range ModelPricing {
+		if pricing.CacheCreate1hRate() != 2*pricing.InputPer1M {
+			t.Errorf("Model %s 1h cache write price = %f, want 2x input (%f)", model, pricing.CacheCreate1hRate(), 2*pricing.InputPer1M)
+		}
+	}
+
+	// Pricing files may omit the 1h price
+	fromFile := Pricing{InputPer1M: 4}
+	if fromFile.CacheCreate1hRate() != 8 {
+		t.Errorf("Default 1h cache write price = %f, want 8", fromFile.CacheCreate1hRate())
+	}
+}
+
+func TestPricing_ForRequest(t *testing.T) {
+	pricing := ModelPricing["claude-sonnet-4-20250514"]
+
+	tests := []struct {
+		name          string
+		usage         TokenUsage
+		expectedInput float64
+	}{
+		{
+			name:          "At the threshold",
+			usage:         TokenUsage{InputTokens: 100_000, CacheReadTokens: 100_000},
+			expectedInput: 3.00,
+		},
+		{
+			name:          "Cache tokens count towards the prompt",
+			usage:         TokenUsage{InputTokens: 1, CacheCreateTokens: 100_000, CacheReadTokens: 100_000},
+			expectedInput: 6.00,
+		},
+		{
+			name:          "Output tokens do not",
+			usage:         TokenUsage{InputTokens: 100_000, OutputTokens: 200_000},
+			expectedInput: 3.00,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pricing.ForRequest(tt.usage).InputPer1M; got != tt.expectedInput {
+				t.Errorf("ForRequest().InputPer1M = %f, want %f", got, tt.expectedInput)
+			}
+		})
+	}
+
+	// Models without a long context tier always use their base rates
+	opus := ModelPricing["claude-opus-4-20250514"]
+	if opus.IsLongContext(TokenUsage{InputTokens: 500_000}) {
+		t.Error("Opus 4 should not have long context rates")
+	}
+
+	custom := Pricing{InputPer1M: 1, LongContext: &LongContextPricing{Threshold: 10, InputPer1M: 2}}
+	if !custom.IsLongContext(TokenUsage{InputTokens: 11}) {
+		t.Error("Custom threshold should be used")
+	}
+}
+
+func TestPricing_WebSearchRate(t *testing.T) {
+	if rate := (Pricing{}).WebSearchRate(); rate != DefaultWebSearchPer1K {
+		t.Errorf("Default web search price = %f, want %f", rate, DefaultWebSearchPer1K)
+	}
+	custom, free := 5.0, 0.0
+	if rate := (Pricing{WebSearchPer1K: &custom}).WebSearchRate(); rate != 5 {
+		t.Errorf("Web search price = %f, want 5", rate)
+	}
+	if rate := (Pricing{WebSearchPer1K: &free}).WebSearchRate(); rate != 0 {
+		t.Errorf("Free web search price = %f, want 0", rate)
+	}
+}
+
+func TestServiceTierMultiplier(t *testing.T) {
+	tests := []struct {
+		tier     string
+		expected float64
+	}{
+		{"", 1},
+		{"standard", 1},
+		{"batch", 0.5},
+		{"Batch", 0.5},
+		{"priority", 1},
+		{"flex", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tier, func(t *testing.T) {
+			if result := ServiceTierMultiplier(tt.tier); result != tt.expected {
+				t.Errorf("ServiceTierMultiplier(%q) = %v, want %v", tt.tier, result, tt.expected)
+			}
+		})
+	}
+}
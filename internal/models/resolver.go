@@ -0,0 +1,155 @@
+package models
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SyntheticModel is the model name Claude Code writes for messages it
+// generates locally, such as API error notices. They are never billed.
+const SyntheticModel = "<synthetic>"
+
+var (
+	// Bedrock cross-region (us.anthropic.), Bedrock (anthropic.) and
+	// OpenRouter-style (anthropic/) prefixes
+	providerPrefix = regexp.MustCompile(`^(?:[a-z]{2,4}\.)?anthropic[./]`)
+	// Bedrock version suffixes such as "-v2:0" or ":0"
+	bedrockSuffix = regexp.MustCompile(`(?:-v\d+)?(?::\d+)?$`)
+	// Vertex snapshots such as "claude-3-5-sonnet-v2@20241022"
+	vertexSnapshot = regexp.MustCompile(`(?:-v\d+)?@(\d{8})$`)
+	dateSuffix     = regexp.MustCompile(`-(\d{8})$`)
+	// "claude-3-5-sonnet" and "claude-sonnet-4-1" naming schemes
+	versionFirst = regexp.MustCompile(`^claude-(\d+(?:-\d)?)-(opus|sonnet|haiku)\b`)
+	familyFirst  = regexp.MustCompile(`^claude-(opus|sonnet|haiku)-(\d+(?:-\d)?)\b`)
+)
+
+var (
+	resolveMu    sync.Mutex
+	resolveCache = map[string]resolution{}
+)
+
+type resolution struct {
+	model    string
+	ok       bool
+	fallback bool
+}
+
+// ResolveModel maps a model ID as written in a transcript to the key of its
+// price. It first tries the ID as is, then with provider prefixes and
+// suffixes removed, and finally falls back to the newest priced model of the
+// same family and version. The second result is false when no price could
+// be found.
+func ResolveModel(model string) (string, bool) {
+	r := resolve(model)
+	return r.model, r.ok
+}
+
+// FallbackModel returns the model whose price is borrowed by model, which has
+// none of its own, e.g. "claude-sonnet-4-20250514" for
+// "claude-sonnet-4-20260101". The second result is false when model is priced
+// by itself or not at all.
+func FallbackModel(model string) (string, bool) {
+	r := resolve(model)
+	return r.model, r.fallback
+}
+
+func resolve(model string) resolution {
+	resolveMu.Lock()
+	defer resolveMu.Unlock()
+
+	r, ok := resolveCache[model]
+	if !ok {
+		r = resolveModel(model)
+		resolveCache[model] = r
+	}
+	return r
+}
+
+func resetResolveCache() {
+	resolveMu.Lock()
+	defer resolveMu.Unlock()
+	resolveCache = map[string]resolution{}
+}
+
+func resolveModel(model string) resolution {
+	if isPriced(model) {
+		return resolution{model: model, ok: true}
+	}
+
+	normalized := NormalizeModel(model)
+	if isPriced(normalized) {
+		return resolution{model: normalized, ok: true}
+	}
+
+	// Only the same family and version share prices: a new minor version,
+	// such as Opus 4.5, may be priced differently from its major version
+	family, version := modelFamily(normalized)
+	if family == "" {
+		return resolution{model: model}
+	}
+	if candidate := newestPriced(family, version); candidate != "" {
+		return resolution{model: candidate, ok: true, fallback: true}
+	}
+
+	return resolution{model: model}
+}
+
+// NormalizeModel strips provider prefixes and suffixes from a model ID, e.g.
+// "anthropic.claude-3-5-sonnet-20241022-v2:0" becomes "claude-3-5-sonnet-20241022".
+func NormalizeModel(model string) string {
+	normalized := strings.ToLower(strings.TrimSpace(model))
+	normalized = providerPrefix.ReplaceAllString(normalized, "")
+	normalized = vertexSnapshot.ReplaceAllString(normalized, "-$1")
+	normalized = bedrockSuffix.ReplaceAllString(normalized, "")
+	return normalized
+}
+
+// modelFamily extracts the family and dotted version, e.g. ("sonnet", "3.5").
+func modelFamily(model string) (string, string) {
+	if m := versionFirst.FindStringSubmatch(model); m != nil {
+		return m[2], strings.ReplaceAll(m[1], "-", ".")
+	}
+	if m := familyFirst.FindStringSubmatch(model); m != nil {
+		return m[1], strings.ReplaceAll(m[2], "-", ".")
+	}
+	return "", ""
+}
+
+func isPriced(model string) bool {
+	if _, ok := ModelPricing[model]; ok {
+		return true
+	}
+	_, ok := PricingHistory[model]
+	return ok
+}
+
+func newestPriced(family, version string) string {
+	var candidates []string
+	for model := range ModelPricing {
+		if f, v := modelFamily(model); f == family && v == version {
+			candidates = append(candidates, model)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	// Dated snapshots sort by their date; the model name breaks ties
+	sort.Slice(candidates, func(i, j int) bool {
+		di, dj := snapshotDate(candidates[i]), snapshotDate(candidates[j])
+		if di != dj {
+			return di > dj
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates[0]
+}
+
+func snapshotDate(model string) string {
+	if m := dateSuffix.FindStringSubmatch(model); m != nil {
+		return m[1]
+	}
+	return ""
+}
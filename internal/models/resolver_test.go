@@ -0,0 +1,115 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeModel(t *testing.T) {
+	tests := []struct {
+		model    string
+		expected string
+	}{
+		{"claude-sonnet-4-20250514", "claude-sonnet-4-20250514"},
+		{"anthropic.claude-3-5-sonnet-20241022-v2:0", "claude-3-5-sonnet-20241022"},
+		{"us.anthropic.claude-3-5-haiku-20241022-v1:0", "claude-3-5-haiku-20241022"},
+		{"anthropic/claude-opus-4-20250514", "claude-opus-4-20250514"},
+		{"claude-3-5-sonnet-v2@20241022", "claude-3-5-sonnet-20241022"},
+		{"claude-opus-4@20250514", "claude-opus-4-20250514"},
+		{"Claude-Sonnet-4-20250514", "claude-sonnet-4-20250514"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			result := NormalizeModel(tt.model)
+			if result != tt.expected {
+				t.Errorf("NormalizeModel(%s) = %s, want %s", tt.model, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		model      string
+		expected   string
+		expectedOK bool
+	}{
+		{"claude-opus-4-20250514", "claude-opus-4-20250514", true},
+		{"anthropic.claude-3-5-sonnet-20241022-v2:0", "claude-3-5-sonnet-20241022", true},
+		// New dated snapshot of a known family and version
+		{"claude-sonnet-4-20260101", "claude-sonnet-4-20250514", true},
+		// Newest snapshot of the family wins
+		{"claude-3-5-sonnet-latest", "claude-3-5-sonnet-20241022", true},
+		// Minor versions are not priced as their major version
+		{"claude-opus-4-1-20250805", "claude-opus-4-1-20250805", false},
+		{"claude-opus-4-5", "claude-opus-4-5", false},
+		{"claude-sonnet-4-5-20250929", "claude-sonnet-4-5-20250929", false},
+		{"claude-3-haiku", "claude-3-haiku-20240307", true},
+		{"claude-5-opus-20300101", "claude-5-opus-20300101", false},
+		{"gpt-4o", "gpt-4o", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			result, ok := ResolveModel(tt.model)
+			if result != tt.expected || ok != tt.expectedOK {
+				t.Errorf("ResolveModel(%s) = (%s, %v), want (%s, %v)", tt.model, result, ok, tt.expected, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestFallbackModel(t *testing.T) {
+	tests := []struct {
+		model      string
+		expected   string
+		expectedOK bool
+	}{
+		{"claude-sonnet-4-20260101", "claude-sonnet-4-20250514", true},
+		{"claude-3-haiku", "claude-3-haiku-20240307", true},
+		// Priced by itself or after normalization
+		{"claude-opus-4-20250514", "claude-opus-4-20250514", false},
+		{"anthropic.claude-3-5-sonnet-20241022-v2:0", "claude-3-5-sonnet-20241022", false},
+		{"claude-opus-4-5", "claude-opus-4-5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			result, ok := FallbackModel(tt.model)
+			if result != tt.expected || ok != tt.expectedOK {
+				t.Errorf("FallbackModel(%s) = (%s, %v), want (%s, %v)", tt.model, result, ok, tt.expected, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestGetPricing_Synthetic(t *testing.T) {
+	pricing, ok := GetPricing(SyntheticModel, time.Now())
+	if !ok {
+		t.Fatal("Synthetic messages should be priced")
+	}
+	if pricing.InputPer1M != 0 || pricing.OutputPer1M != 0 || pricing.CacheCreatePer1M != 0 || pricing.CacheReadPer1M != 0 || pricing.WebSearchRate() != 0 {
+		t.Errorf("Synthetic pricing = %+v, want zero", pricing)
+	}
+}
+
+func TestResolveModel_AfterPricingFile(t *testing.T) {
+	defer func() {
+		delete(ModelPricing, "claude-opus-4-1-20250805")
+		PricingSources = map[string]string{}
+		resetResolveCache()
+	}()
+
+	if resolved, ok := ResolveModel("claude-opus-4-1-20250805"); ok {
+		t.Fatalf("Before pricing file: resolved to %s, want no price", resolved)
+	}
+
+	ApplyPricingFile(&PricingFile{Models: map[string]PricingPeriods{
+		"claude-opus-4-1-20250805": {{InputPer1M: 15, OutputPer1M: 75}},
+	}}, "pricing.json")
+
+	if resolved, _ := ResolveModel("claude-opus-4-1-20250805"); resolved != "claude-opus-4-1-20250805" {
+		t.Errorf("After pricing file: resolved to %s, want claude-opus-4-1-20250805", resolved)
+	}
+}
@@ -4,30 +4,71 @@ import (
 	"time"
 )
 
+// TokenUsage counts tokens by type. CacheCreateTokens are 5-minute cache
+// writes, including writes recorded before the 5m/1h split existed, and
+// CacheCreate1hTokens are 1-hour cache writes. WebSearchRequests counts
+// server-side web searches, which are billed per request rather than per
+// token and so are not part of Total.
 type TokenUsage struct {
-	InputTokens       int
-	OutputTokens      int
-	CacheCreateTokens int
-	CacheReadTokens   int
+	InputTokens         int
+	OutputTokens        int
+	CacheCreateTokens   int
+	CacheCreate1hTokens int
+	CacheReadTokens     int
+	WebSearchRequests   int
 }
 
 func (t TokenUsage) Total() int {
-	return t.InputTokens + t.OutputTokens + t.CacheCreateTokens + t.CacheReadTokens
+	return t.InputTokens + t.OutputTokens + t.CacheCreateTokens + t.CacheCreate1hTokens + t.CacheReadTokens
+}
+
+// PromptTokens is the size of the prompt: input plus cache tokens.
+func (t TokenUsage) PromptTokens() int {
+	return t.InputTokens + t.CacheCreateTokens + t.CacheCreate1hTokens + t.CacheReadTokens
+}
+
+func (t *TokenUsage) Add(other TokenUsage) {
+	t.InputTokens += other.InputTokens
+	t.OutputTokens += other.OutputTokens
+	t.CacheCreateTokens += other.CacheCreateTokens
+	t.CacheCreate1hTokens += other.CacheCreate1hTokens
+	t.CacheReadTokens += other.CacheReadTokens
+	t.WebSearchRequests += other.WebSearchRequests
 }
 
 type Message struct {
-	SessionID        string    `json:"session_id"`
-	Timestamp        time.Time `json:"timestamp"`
-	Model            string    `json:"model"`
-	TokenUsage       TokenUsage
-	EstimatedCostUSD float64
+	SessionID   string    `json:"session_id"`
+	MessageID   string    `json:"message_id,omitempty"`
+	RequestID   string    `json:"request_id,omitempty"`
+	Project     string    `json:"project,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	Model       string    `json:"model"`
+	ServiceTier string    `json:"service_tier"`
+	TokenUsage  TokenUsage
+	// EstimatedCost is the cost of the message in the report currency
+	EstimatedCost Money
+	// RecordedCostUSD is the costUSD the client wrote to the transcript, if any
+	RecordedCostUSD *Money `json:"recorded_cost_usd,omitempty"`
 }
 
+// DailyUsage totals the messages of a day. Its Cost, like those of the other
+// aggregates below, is in the report currency.
 type DailyUsage struct {
 	Date       time.Time
 	Models     []string
 	TokenUsage TokenUsage
-	CostUSD    float64
+	Cost       Money
+}
+
+// WeeklyUsage identifies a week by its first day. Year and Week are the ISO
+// year and week number of the Monday in that week.
+type WeeklyUsage struct {
+	Year       int
+	Week       int
+	StartDate  time.Time
+	Models     []string
+	TokenUsage TokenUsage
+	Cost       Money
 }
 
 type MonthlyUsage struct {
@@ -35,7 +76,7 @@ type MonthlyUsage struct {
 	Month      time.Month
 	Models     []string
 	TokenUsage TokenUsage
-	CostUSD    float64
+	Cost       Money
 }
 
 type SessionUsage struct {
@@ -44,15 +85,102 @@ type SessionUsage struct {
 	EndTime    time.Time
 	Models     []string
 	TokenUsage TokenUsage
-	CostUSD    float64
+	Cost       Money
+}
+
+type ServiceTierUsage struct {
+	ServiceTier string
+	Models      []string
+	TokenUsage  TokenUsage
+	Cost        Money
+}
+
+type ProjectUsage struct {
+	Project    string
+	Models     []string
+	TokenUsage TokenUsage
+	Cost       Money
+}
+
+// BlockUsage is a 5-hour billing window. EndTime is when the window closes,
+// LastActivity is the last message inside it.
+type BlockUsage struct {
+	StartTime    time.Time
+	EndTime      time.Time
+	LastActivity time.Time
+	IsActive     bool
+	Models       []string
+	TokenUsage   TokenUsage
+	Cost         Money
+	Projection   *BlockProjection `json:",omitempty"`
+}
+
+// BlockProjection extrapolates the active block to its end at the current burn rate.
+type BlockProjection struct {
+	Elapsed         time.Duration
+	Remaining       time.Duration
+	TokensPerMinute float64
+	CostPerHour     Money
+	ProjectedTokens int
+	ProjectedCost   Money
 }
 
 type ModelBreakdown struct {
+	Model       string
+	PricePeriod string `json:",omitempty"`
+	LongContext bool   `json:",omitempty"`
+	ServiceTier string
+	TokenUsage  TokenUsage
+	Cost        Money
+}
+
+// UnpricedModel is a model for which no price of its own was found. Its
+// usage was counted as $0, or, when PricedAs is set, priced as that model.
+type UnpricedModel struct {
 	Model      string
+	PricedAs   string
+	Pricing    Pricing
+	Messages   int
 	TokenUsage TokenUsage
-	CostUSD    float64
 }
 
+// CostAudit compares recorded and calculated costs for the messages of a
+// model that carry both. Costs are in the report currency.
+type CostAudit struct {
+	Model          string
+	Messages       int
+	RecordedCost   Money
+	CalculatedCost Money
+	// Difference is how much the calculated cost exceeds the recorded one
+	Difference Money
+}
+
+// CostMode selects where message costs come from.
+type CostMode string
+
+const (
+	// CostModeAuto uses the recorded cost when present and calculates it otherwise
+	CostModeAuto CostMode = "auto"
+	// CostModeCalculate always calculates costs from tokens and pricing
+	CostModeCalculate CostMode = "calculate"
+	// CostModeDisplay only uses recorded costs; messages without one are $0
+	CostModeDisplay CostMode = "display"
+)
+
+// OutputFormat selects how reports are written.
+type OutputFormat string
+
+const (
+	FormatTable OutputFormat = "table"
+	FormatJSON  OutputFormat = "json"
+	FormatCSV   OutputFormat = "csv"
+	FormatTSV   OutputFormat = "tsv"
+	// FormatMarkdown writes GitHub-flavored Markdown tables
+	FormatMarkdown OutputFormat = "markdown"
+	// FormatHTML writes a self-contained HTML page
+	FormatHTML OutputFormat = "html"
+)
+
 type ReportOptions struct {
 	Since      *time.Time
 	Until      *time.Time
@@ -60,4 +188,29 @@ type ReportOptions struct {
 	JSONOutput bool
 	Ascending  bool
 	Models     []string
+	NoDedupe   bool
+	Verbose    bool
+	Jobs       int
+	NoCache    bool
+	DataDirs   []string
+	Projects   []string
+	// ServiceTiers filters by service tier; empty means all tiers
+	ServiceTiers []string
+	Location     *time.Location
+	WeekStart    time.Weekday
+	CostMode     CostMode
+	Currency     string
+	// ExchangeRate converts costs to Currency; nil means US dollars
+	ExchangeRate *ExchangeRate
+	// Decimals is the number of decimal places costs are rounded to
+	Decimals int
+	Format   OutputFormat
+	// Totals appends a totals row to CSV and TSV output
+	Totals bool
+	// Template is a text/template the report is written with instead of Format
+	Template string
+	// PricingFile and RatesFile are the pricing and exchange rate files
+	// loaded, if any
+	PricingFile string
+	RatesFile   string
 }
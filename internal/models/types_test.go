@@ -29,6 +29,20 @@ func TestTokenUsage_Total(t *testing.T) {
 			},
 			expected: 300,
 		},
+		{
+			name: "Both cache write durations",
+			usage: TokenUsage{
+				InputTokens:         100,
+				CacheCreateTokens:   50,
+				CacheCreate1hTokens: 30,
+			},
+			expected: 180,
+		},
+		{
+			name:     "Web searches are not tokens",
+			usage:    TokenUsage{InputTokens: 100, WebSearchRequests: 5},
+			expected: 100,
+		},
 		{
 			name:     "Empty usage",
 			usage:    TokenUsage{},
@@ -56,6 +70,16 @@ func TestTokenUsage_Total(t *testing.T) {
 	}
 }
 
+func TestTokenUsage_Add(t *testing.T) {
+	usage := TokenUsage{InputTokens: 1, OutputTokens: 2, CacheCreateTokens: 3, CacheCreate1hTokens: 4, CacheReadTokens: 5, WebSearchRequests: 6}
+	usage.Add(TokenUsage{InputTokens: 10, OutputTokens: 20, CacheCreateTokens: 30, CacheCreate1hTokens: 40, CacheReadTokens: 50, WebSearchRequests: 60})
+
+	expected := TokenUsage{InputTokens: 11, OutputTokens: 22, CacheCreateTokens: 33, CacheCreate1hTokens: 44, CacheReadTokens: 55, WebSearchRequests: 66}
+	if usage != expected {
+		t.Errorf("Add() = %+v, want %+v", usage, expected)
+	}
+}
+
 func TestReportOptions(t *testing.T) {
 	// Test that ReportOptions can be properly initialized
 	now := time.Now()
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/t-ishitsuka/claude-usage-go/internal/models"
+)
+
+// Bump cacheVersion whenever models.Message or the extraction rules change,
+// so that stale caches are discarded instead of decoded.
+const cacheVersion = 8
+
+const cacheFileName = "parse-cache.gob"
+
+type fileCache struct {
+	Version int
+	Files   map[string]*cacheEntry
+}
+
+// cacheEntry holds the messages extracted from one file. Offset is where
+// parsing stopped, so an append-only file can resume from there.
+type cacheEntry struct {
+	Size     int64
+	ModTime  time.Time
+	Offset   int64
+	Messages []models.Message
+}
+
+func GetCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "claude-usage-go")
+}
+
+func ClearCache(dir string) error {
+	err := os.Remove(filepath.Join(dir, cacheFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func newFileCache() *fileCache {
+	return &fileCache{
+		Version: cacheVersion,
+		Files:   make(map[string]*cacheEntry),
+	}
+}
+
+// loadCache never fails: a missing, unreadable or outdated cache simply
+// results in an empty one and every file being parsed from scratch.
+func loadCache(dir string) *fileCache {
+	file, err := os.Open(filepath.Join(dir, cacheFileName))
+	if err != nil {
+		return newFileCache()
+	}
+	defer file.Close()
+
+	var cache fileCache
+	if err := gob.NewDecoder(file).Decode(&cache); err != nil || cache.Version != cacheVersion || cache.Files == nil {
+		return newFileCache()
+	}
+	return &cache
+}
+
+func saveCache(dir string, cache *fileCache) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so concurrent runs never see a partial cache
+	tmp, err := os.CreateTemp(dir, cacheFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(cache); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, cacheFileName))
+}
+
+// parseFileCached returns the messages of a file, reusing the cached entry
+// when the file is unchanged and parsing only the appended bytes when it grew.
+// Files last modified before since are skipped without being opened.
+func parseFileCached(file jsonlFile, cached *cacheEntry, since, until *time.Time) (fileResult, error) {
+	info, err := os.Stat(file.Path)
+	if err != nil {
+		return fileResult{}, err
+	}
+
+	if since != nil && info.ModTime().Before(*since) {
+		return fileResult{status: fileSkipped}, nil
+	}
+
+	if cached != nil && cached.Size == info.Size() && cached.ModTime.Equal(info.ModTime()) {
+		return fileResult{entry: cached, status: fileCached}, nil
+	}
+
+	var offset int64
+	var messages []models.Message
+	if cached != nil && info.Size() > cached.Size && cached.Offset <= info.Size() {
+		offset = cached.Offset
+		messages = append(messages, cached.Messages...)
+	}
+
+	msgs, offset, truncated, err := parseJSONLFile(file, offset, until)
+	if err != nil {
+		return fileResult{}, err
+	}
+
+	status := fileParsed
+	if truncated {
+		status = fileTruncated
+	}
+
+	return fileResult{
+		entry: &cacheEntry{
+			Size:     info.Size(),
+			ModTime:  info.ModTime(),
+			Offset:   offset,
+			Messages: append(messages, msgs...),
+		},
+		status: status,
+	}, nil
+}
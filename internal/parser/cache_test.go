@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const cacheTestLine = `{"sessionId":"s1","timestamp":"2025-01-15T10:00:00.000Z","type":"assistant","message":{"role":"assistant","model":"claude-opus-4-20250514","usage":{"input_tokens":100,"output_tokens":200}}}
+`
+
+func TestParseJSONLFilesWithOptions_Cache(t *testing.T) {
+	dataDir := t.TempDir()
+	cacheDir := t.TempDir()
+	testFile := filepath.Join(dataDir, "test.jsonl")
+
+	if err := os.WriteFile(testFile, []byte(cacheTestLine), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ParseOptions{CacheDir: cacheDir}
+
+	// First run populates the cache
+	messages, stats, err := ParseJSONLFilesWithOptions([]string{dataDir}, opts)
+	if err != nil {
+		t.Fatalf("ParseJSONLFilesWithOptions() error = %v", err)
+	}
+	if len(messages) != 1 || stats.CachedFiles != 0 {
+		t.Fatalf("First run: got %d messages, %d cached files, want 1, 0", len(messages), stats.CachedFiles)
+	}
+
+	// Second run is served from the cache
+	messages, stats, err = ParseJSONLFilesWithOptions([]string{dataDir}, opts)
+	if err != nil {
+		t.Fatalf("ParseJSONLFilesWithOptions() error = %v", err)
+	}
+	if len(messages) != 1 || stats.CachedFiles != 1 {
+		t.Fatalf("Second run: got %d messages, %d cached files, want 1, 1", len(messages), stats.CachedFiles)
+	}
+
+	// Appending resumes from the stored offset without duplicating messages
+	f, err := os.OpenFile(testFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(cacheTestLine); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(testFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, stats, err = ParseJSONLFilesWithOptions([]string{dataDir}, opts)
+	if err != nil {
+		t.Fatalf("ParseJSONLFilesWithOptions() error = %v", err)
+	}
+	if len(messages) != 2 || stats.CachedFiles != 0 {
+		t.Fatalf("After append: got %d messages, %d cached files, want 2, 0", len(messages), stats.CachedFiles)
+	}
+
+	// Clearing the cache forces a full parse
+	if err := ClearCache(cacheDir); err != nil {
+		t.Fatalf("ClearCache() error = %v", err)
+	}
+	if err := ClearCache(cacheDir); err != nil {
+		t.Fatalf("ClearCache() on missing cache error = %v", err)
+	}
+	messages, stats, err = ParseJSONLFilesWithOptions([]string{dataDir}, opts)
+	if err != nil {
+		t.Fatalf("ParseJSONLFilesWithOptions() error = %v", err)
+	}
+	if len(messages) != 2 || stats.CachedFiles != 0 {
+		t.Fatalf("After clear: got %d messages, %d cached files, want 2, 0", len(messages), stats.CachedFiles)
+	}
+}
+
+func TestParseJSONLFile_PartialLastLine(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.jsonl")
+	partial := `{"sessionId":"s1","timestamp":"2025-01-15T11:00`
+	if err := os.WriteFile(testFile, []byte(cacheTestLine+partial), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, offset, _, err := parseJSONLFile(jsonlFile{Path: testFile}, 0, nil)
+	if err != nil {
+		t.Fatalf("parseJSONLFile() error = %v", err)
+	}
+	if len(messages) != 1 {
+		t.Errorf("Got %d messages, want 1", len(messages))
+	}
+	if offset != int64(len(cacheTestLine)) {
+		t.Errorf("Offset = %d, want %d (before the partial line)", offset, len(cacheTestLine))
+	}
+}
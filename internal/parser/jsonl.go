@@ -4,36 +4,216 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/t-ishitsuka/claude-usage-go/internal/models"
 )
 
 type JSONLEntry struct {
-	SessionID string    `json:"sessionId"`
-	Timestamp time.Time `json:"timestamp"`
-	Type      string    `json:"type"`
-	Message   *Message  `json:"message,omitempty"`
+	SessionID string        `json:"sessionId"`
+	RequestID string        `json:"requestId"`
+	CWD       string        `json:"cwd"`
+	Timestamp time.Time     `json:"timestamp"`
+	Type      string        `json:"type"`
+	Message   *Message      `json:"message,omitempty"`
+	CostUSD   *models.Money `json:"costUSD,omitempty"`
 }
 
 type Message struct {
+	ID    string `json:"id"`
 	Role  string `json:"role"`
 	Model string `json:"model"`
 	Usage *Usage `json:"usage,omitempty"`
 }
 
 type Usage struct {
-	InputTokens       int `json:"input_tokens"`
-	OutputTokens      int `json:"output_tokens"`
-	CacheCreateTokens int `json:"cache_creation_input_tokens"`
-	CacheReadTokens   int `json:"cache_read_input_tokens"`
+	InputTokens       int            `json:"input_tokens"`
+	OutputTokens      int            `json:"output_tokens"`
+	CacheCreateTokens int            `json:"cache_creation_input_tokens"`
+	CacheReadTokens   int            `json:"cache_read_input_tokens"`
+	CacheCreation     *CacheCreation `json:"cache_creation,omitempty"`
+	ServerToolUse     *ServerToolUse `json:"server_tool_use,omitempty"`
+	ServiceTier       string         `json:"service_tier,omitempty"`
+}
+
+// ServerToolUse counts the server-side tools run for a request.
+type ServerToolUse struct {
+	WebSearchRequests int `json:"web_search_requests"`
+}
+
+// CacheCreation splits cache writes by their time to live.
+type CacheCreation struct {
+	Ephemeral5mInputTokens int `json:"ephemeral_5m_input_tokens"`
+	Ephemeral1hInputTokens int `json:"ephemeral_1h_input_tokens"`
+}
+
+// TokenUsage converts the usage object, keeping 1-hour cache writes apart.
+// Cache writes not covered by the split are counted as 5-minute writes.
+func (u *Usage) TokenUsage() models.TokenUsage {
+	usage := models.TokenUsage{
+		InputTokens:       u.InputTokens,
+		OutputTokens:      u.OutputTokens,
+		CacheCreateTokens: u.CacheCreateTokens,
+		CacheReadTokens:   u.CacheReadTokens,
+	}
+
+	if u.CacheCreation != nil {
+		total := u.CacheCreateTokens
+		if split := u.CacheCreation.Ephemeral5mInputTokens + u.CacheCreation.Ephemeral1hInputTokens; split > total {
+			total = split
+		}
+		usage.CacheCreate1hTokens = u.CacheCreation.Ephemeral1hInputTokens
+		usage.CacheCreateTokens = total - usage.CacheCreate1hTokens
+	}
+	if u.ServerToolUse != nil {
+		usage.WebSearchRequests = u.ServerToolUse.WebSearchRequests
+	}
+
+	return usage
+}
+
+type ParseOptions struct {
+	NoDedupe bool
+	Jobs     int
+	// CacheDir enables the persistent parse cache when non-empty
+	CacheDir string
+	// Since and Until prune files outside the date range before parsing;
+	// they use the same semantics as FilterByDateRange.
+	Since *time.Time
+	Until *time.Time
+}
+
+// Increase buffer size to handle large lines (10MB)
+const maxLineSize = 10 * 1024 * 1024
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, maxLineSize)
+		return &buf
+	},
+}
+
+type ParseStats struct {
+	Files          int
+	CachedFiles    int
+	SkippedFiles   int
+	TruncatedFiles int
+	Duplicates     int
+}
+
+// PrunedFiles is the number of files that were not fully parsed because of
+// the date range.
+func (s ParseStats) PrunedFiles() int {
+	return s.SkippedFiles + s.TruncatedFiles
+}
+
+type fileStatus int
+
+const (
+	fileParsed fileStatus = iota
+	fileCached
+	// fileSkipped files were last written before the start of the date range
+	fileSkipped
+	// fileTruncated files were parsed only up to the end of the date range
+	fileTruncated
+)
+
+// jsonlFile is a transcript together with the encoded project directory it
+// was found in, e.g. "-Users-me-src-app" for ~/.claude/projects/-Users-me-src-app/<session>.jsonl.
+type jsonlFile struct {
+	Path       string
+	ProjectDir string
+}
+
+type fileResult struct {
+	entry  *cacheEntry
+	status fileStatus
 }
 
 func ParseJSONLFiles(directory string) ([]models.Message, error) {
+	messages, _, err := ParseJSONLFilesWithOptions([]string{directory}, ParseOptions{})
+	return messages, err
+}
+
+// ParseJSONLFilesWithOptions parses the transcripts of all directories as
+// one data set, so duplicates are also dropped across directories.
+func ParseJSONLFilesWithOptions(directories []string, opts ParseOptions) ([]models.Message, ParseStats, error) {
+	var stats ParseStats
+
+	var files []jsonlFile
+	for _, directory := range directories {
+		dirFiles, err := findJSONLFiles(directory)
+		if err != nil {
+			return nil, stats, fmt.Errorf("error walking directory: %w", err)
+		}
+		files = append(files, dirFiles...)
+	}
+	stats.Files = len(files)
+
+	cache := newFileCache()
+	if opts.CacheDir != "" {
+		cache = loadCache(opts.CacheDir)
+	}
+
+	var until *time.Time
+	if opts.Until != nil {
+		t := untilBound(*opts.Until)
+		until = &t
+	}
+
+	results, err := parseFilesConcurrently(files, cache, opts.Since, until, opts.Jobs)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	// Merge in walk order so output does not depend on scheduling
 	var messages []models.Message
+	updated := newFileCache()
+	for i, result := range results {
+		switch result.status {
+		case fileSkipped:
+			stats.SkippedFiles++
+		case fileTruncated:
+			stats.TruncatedFiles++
+		case fileCached:
+			stats.CachedFiles++
+		}
+
+		if result.status != fileSkipped {
+			messages = append(messages, result.entry.Messages...)
+		}
+
+		// Pruned files were not read completely, so keep whatever the cache
+		// already knew about them rather than storing a partial entry.
+		if result.status == fileSkipped || result.status == fileTruncated {
+			if old, ok := cache.Files[files[i].Path]; ok {
+				updated.Files[files[i].Path] = old
+			}
+			continue
+		}
+		updated.Files[files[i].Path] = result.entry
+	}
+
+	if opts.CacheDir != "" {
+		// A cache that cannot be written only costs speed on the next run
+		_ = saveCache(opts.CacheDir, updated)
+	}
+
+	if !opts.NoDedupe {
+		messages, stats.Duplicates = dedupeMessages(messages)
+	}
+
+	return messages, stats, nil
+}
+
+func findJSONLFiles(directory string) ([]jsonlFile, error) {
+	var files []jsonlFile
 
 	// Walk the directory tree to find all .jsonl files
 	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
@@ -42,73 +222,266 @@ func ParseJSONLFiles(directory string) ([]models.Message, error) {
 		}
 
 		if !info.IsDir() && filepath.Ext(path) == ".jsonl" {
-			msgs, err := parseJSONLFile(path)
-			if err != nil {
-				return fmt.Errorf("error parsing %s: %w", path, err)
-			}
-			messages = append(messages, msgs...)
+			files = append(files, jsonlFile{
+				Path:       path,
+				ProjectDir: projectDirOf(directory, path),
+			})
 		}
 
 		return nil
 	})
 
+	return files, err
+}
+
+// projectDirOf returns the first directory below the projects root that
+// contains path, or "" when the file sits directly in the root.
+func projectDirOf(root, path string) string {
+	rel, err := filepath.Rel(root, path)
 	if err != nil {
-		return nil, fmt.Errorf("error walking directory: %w", err)
+		return ""
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 2 {
+		return ""
 	}
+	return parts[0]
+}
 
-	return messages, nil
+// DecodeProjectDir turns an encoded project directory name back into a path.
+// Claude Code replaces path separators with "-", so the result is a best
+// effort: dashes that were part of a directory name also become separators.
+func DecodeProjectDir(name string) string {
+	if !strings.HasPrefix(name, "-") {
+		return name
+	}
+	return strings.ReplaceAll(name, "-", "/")
 }
 
-func parseJSONLFile(filename string) ([]models.Message, error) {
-	file, err := os.Open(filename)
+// parseFilesConcurrently parses files on a pool of jobs workers and returns
+// the result of each file at the same index as the file itself.
+func parseFilesConcurrently(files []jsonlFile, cache *fileCache, since, until *time.Time, jobs int) ([]fileResult, error) {
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+	if jobs > len(files) {
+		jobs = len(files)
+	}
+
+	results := make([]fileResult, len(files))
+	errs := make([]error, len(files))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = parseFileCached(files[i], cache.Files[files[i].Path], since, until)
+			}
+		}()
+	}
+
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", files[i].Path, err)
+		}
+	}
+
+	return results, nil
+}
+
+// dedupeMessages drops repeated API responses, which Claude Code writes into
+// several transcripts when a session is resumed or branched. Entries lacking
+// either a message ID or a request ID cannot be matched and are always kept.
+func dedupeMessages(messages []models.Message) ([]models.Message, int) {
+	seen := make(map[string]bool)
+	result := messages[:0]
+	duplicates := 0
+
+	for _, msg := range messages {
+		if msg.MessageID != "" && msg.RequestID != "" {
+			key := msg.MessageID + ":" + msg.RequestID
+			if seen[key] {
+				duplicates++
+				continue
+			}
+			seen[key] = true
+		}
+		result = append(result, msg)
+	}
+
+	return result, duplicates
+}
+
+// parseJSONLFile parses a file starting at offset and returns its messages
+// together with the offset just past the last complete line consumed. Each
+// message is attributed to its cwd, falling back to the decoded project
+// directory for entries written without one. When
+// until is set, parsing stops at the first entry timestamped after it and
+// truncated is reported; transcripts are append-only, so later entries are
+// never older.
+func parseJSONLFile(jf jsonlFile, offset int64, until *time.Time) (messages []models.Message, next int64, truncated bool, err error) {
+	file, err := os.Open(jf.Path)
 	if err != nil {
-		return nil, err
+		return nil, offset, false, err
 	}
 	defer file.Close()
 
-	var messages []models.Message
+	if offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return nil, offset, false, err
+		}
+	}
+
+	fallbackProject := DecodeProjectDir(jf.ProjectDir)
 	scanner := bufio.NewScanner(file)
 
-	// Increase buffer size to handle large lines (10MB)
-	const maxCapacity = 10 * 1024 * 1024
-	buf := make([]byte, maxCapacity)
-	scanner.Buffer(buf, maxCapacity)
+	buf := bufferPool.Get().(*[]byte)
+	defer bufferPool.Put(buf)
+	scanner.Buffer(*buf, maxLineSize)
+
+	// Track how many bytes each line consumed, including its line ending
+	var advance int
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		n, token, err := bufio.ScanLines(data, atEOF)
+		advance = n
+		return n, token, err
+	})
 
 	for scanner.Scan() {
+		line := scanner.Bytes()
+		terminated := advance > len(line)
+
 		var entry JSONLEntry
-		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// An unterminated line that doesn't parse is probably still being
+			// written, so leave the offset in front of it for the next run.
+			if terminated {
+				offset += int64(advance)
+			}
 			continue
 		}
 
+		if until != nil && entry.Timestamp.After(*until) {
+			truncated = true
+			break
+		}
+		offset += int64(advance)
+
 		if entry.Type == "assistant" && entry.Message != nil && entry.Message.Role == "assistant" && entry.Message.Usage != nil {
 			msg := models.Message{
-				SessionID: entry.SessionID,
-				Timestamp: entry.Timestamp,
-				Model:     entry.Message.Model,
-				TokenUsage: models.TokenUsage{
-					InputTokens:       entry.Message.Usage.InputTokens,
-					OutputTokens:      entry.Message.Usage.OutputTokens,
-					CacheCreateTokens: entry.Message.Usage.CacheCreateTokens,
-					CacheReadTokens:   entry.Message.Usage.CacheReadTokens,
-				},
+				SessionID:       entry.SessionID,
+				MessageID:       entry.Message.ID,
+				RequestID:       entry.RequestID,
+				Project:         entry.CWD,
+				Timestamp:       entry.Timestamp,
+				Model:           entry.Message.Model,
+				ServiceTier:     models.NormalizeServiceTier(entry.Message.Usage.ServiceTier),
+				TokenUsage:      entry.Message.Usage.TokenUsage(),
+				RecordedCostUSD: entry.CostUSD,
+			}
+			if msg.Project == "" {
+				msg.Project = fallbackProject
 			}
 			messages = append(messages, msg)
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return nil, offset, false, err
 	}
 
-	return messages, nil
+	return messages, offset, truncated, nil
 }
 
-func GetClaudeProjectsDir() string {
+// GetClaudeConfigDirs returns the Claude config directories to search, in
+// order. CLAUDE_CONFIG_DIR (a comma-separated list) replaces the defaults,
+// which are ~/.config/claude for newer Claude Code versions and ~/.claude.
+func GetClaudeConfigDirs() []string {
+	if env := os.Getenv("CLAUDE_CONFIG_DIR"); strings.TrimSpace(env) != "" {
+		var dirs []string
+		for _, dir := range strings.Split(env, ",") {
+			if dir = strings.TrimSpace(dir); dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+		return dirs
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return ""
+		return nil
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(homeDir, ".config")
+	}
+
+	return []string{
+		filepath.Join(configHome, "claude"),
+		filepath.Join(homeDir, ".claude"),
+	}
+}
+
+// ResolveProjectsDirs returns the existing projects directories for the given
+// data directories, or for GetClaudeConfigDirs when none are given. A data
+// directory may be either a config directory containing "projects" or, when
+// given explicitly through dataDirs or CLAUDE_CONFIG_DIR, a projects
+// directory itself. Paths are returned once even if listed twice.
+func ResolveProjectsDirs(dataDirs []string) ([]string, error) {
+	candidates := dataDirs
+	explicit := true
+	if len(candidates) == 0 {
+		candidates = GetClaudeConfigDirs()
+		explicit = strings.TrimSpace(os.Getenv("CLAUDE_CONFIG_DIR")) != ""
+	}
+
+	var resolved []string
+	seen := make(map[string]bool)
+	for _, dir := range candidates {
+		projectsDir := filepath.Join(dir, "projects")
+		if !isDir(projectsDir) {
+			// A default config dir without projects holds no usage data
+			if !explicit || !isDir(dir) {
+				continue
+			}
+			projectsDir = dir
+		}
+
+		key := filepath.Clean(projectsDir)
+		if abs, err := filepath.Abs(key); err == nil {
+			key = abs
+		}
+		if real, err := filepath.EvalSymlinks(key); err == nil {
+			key = real
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		resolved = append(resolved, projectsDir)
+	}
+
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no Claude data directory found (searched: %s); use --data-dir or CLAUDE_CONFIG_DIR to point at one",
+			strings.Join(candidates, ", "))
 	}
-	return filepath.Join(homeDir, ".claude", "projects")
+
+	return resolved, nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
 
 func FilterByDateRange(messages []models.Message, since, until *time.Time) []models.Message {
@@ -117,7 +490,7 @@ func FilterByDateRange(messages []models.Message, since, until *time.Time) []mod
 		if since != nil && msg.Timestamp.Before(*since) {
 			continue
 		}
-		if until != nil && msg.Timestamp.After(until.Add(24*time.Hour)) {
+		if until != nil && msg.Timestamp.After(untilBound(*until)) {
 			continue
 		}
 		filtered = append(filtered, msg)
@@ -125,6 +498,62 @@ func FilterByDateRange(messages []models.Message, since, until *time.Time) []mod
 	return filtered
 }
 
+// FilterByProjects keeps messages whose project matches one of the given
+// names, either as the full project path or as its last path element.
+func FilterByProjects(messages []models.Message, projects []string) []models.Message {
+	if len(projects) == 0 {
+		return messages
+	}
+
+	projectSet := make(map[string]bool)
+	for _, p := range projects {
+		projectSet[p] = true
+	}
+
+	var filtered []models.Message
+	for _, msg := range messages {
+		if projectSet[msg.Project] || projectSet[filepath.Base(msg.Project)] {
+			filtered = append(filtered, msg)
+		}
+	}
+	return filtered
+}
+
+// FilterByServiceTiers keeps the messages of the given service tiers.
+func FilterByServiceTiers(messages []models.Message, tiers []string) []models.Message {
+	if len(tiers) == 0 {
+		return messages
+	}
+
+	tierSet := make(map[string]bool)
+	for _, tier := range tiers {
+		tierSet[models.NormalizeServiceTier(tier)] = true
+	}
+
+	var filtered []models.Message
+	for _, msg := range messages {
+		if tierSet[models.NormalizeServiceTier(msg.ServiceTier)] {
+			filtered = append(filtered, msg)
+		}
+	}
+	return filtered
+}
+
+// untilBound makes the until date inclusive of the whole day. AddDate keeps
+// this correct on days that are not 24 hours long because of DST.
+func untilBound(until time.Time) time.Time {
+	return until.AddDate(0, 0, 1)
+}
+
+// InLocation converts message timestamps to loc, so that every later grouping
+// by day, month or hour happens in that time zone.
+func InLocation(messages []models.Message, loc *time.Location) []models.Message {
+	for i := range messages {
+		messages[i].Timestamp = messages[i].Timestamp.In(loc)
+	}
+	return messages
+}
+
 func FilterByModels(messages []models.Message, modelList []string) []models.Message {
 	if len(modelList) == 0 {
 		return messages